@@ -0,0 +1,90 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Export the check of the block selection and describe the invalid block.
+//
+
+// This file contains the selection of the blocks that are cracked.
+//
+// Often only a part of a message matters, e.g. a session ID in one block,
+// or only the last block to learn the length of the padding.
+// Cracking only these blocks, or stopping when only a known prefix is left, saves a lot of queries.
+
+package attack
+
+import (
+	"errors"
+	"fmt"
+	"padora/padding"
+	"slices"
+)
+
+// ******** Public variables ********
+
+// ErrInvalidBlockIndex signals that a selected block does not exist in the encrypted message.
+var ErrInvalidBlockIndex = errors.New(`selected block does not exist in the encrypted message`)
+
+// ******** Public functions ********
+
+// CheckBlockSelection checks whether all selected blocks exist in an encrypted message with a number of blocks,
+// including the initialization vector.
+// The initialization vector can not be selected.
+// It is called by the cracker, but it can be called before to avoid queries with an invalid selection.
+func CheckBlockSelection(blocks []int, blockCount int) error {
+	for _, block := range blocks {
+		if block < 1 || block >= blockCount {
+			return fmt.Errorf(`%w: block %d, the encrypted message has the blocks 1 to %d`,
+				ErrInvalidBlockIndex,
+				block,
+				blockCount-1)
+		}
+	}
+
+	return nil
+}
+
+// ******** Private functions ********
+
+// selectsBlock checks whether a block is selected to be cracked.
+func (o Options) selectsBlock(blockIndex int) bool {
+	return len(o.Blocks) == 0 || slices.Contains(o.Blocks, blockIndex)
+}
+
+// getPaddingLength returns the length of the padding of a padded message.
+// It returns 0, if the last block has not been recovered or its padding is invalid.
+func getPaddingLength(paddedMessage []byte, blockSize int, scheme padding.Scheme, recovered []bool) int {
+	if !recovered[len(recovered)-1] {
+		return 0
+	}
+
+	lastBlock := paddedMessage[len(paddedMessage)-blockSize:]
+	unpaddedBlock, err := scheme.Unpad(lastBlock, blockSize)
+	if err != nil {
+		return 0
+	}
+
+	return blockSize - len(unpaddedBlock)
+}
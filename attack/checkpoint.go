@@ -0,0 +1,348 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package attack.
+//    2026-10-18: V2.1.0: Blocks that have not been selected may be missing.
+//    2026-10-18: V2.2.0: Confirm the intermediate bytes of a checkpoint with the oracle.
+//
+
+// This file contains the checkpoints that make it possible to resume cracking.
+//
+// A checkpoint only contains what the attacker knows: the encrypted message, the block size,
+// the padding scheme and the recovered intermediate bytes, i.e. the decrypted bytes before
+// they are XOR-ed with the previous block.
+// It is written periodically while cracking and when cracking stops.
+
+package attack
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"padora/oracle"
+	"padora/padding"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// ******** Public types ********
+
+// Checkpoint is the state of cracking an encrypted message.
+type Checkpoint struct {
+	// EncryptedMessage is the hex encoded encrypted message including the initialization vector.
+	EncryptedMessage string `json:"encryptedMessage"`
+	// BlockSize is the block size.
+	BlockSize int `json:"blockSize"`
+	// PaddingScheme is the name of the padding scheme.
+	PaddingScheme string `json:"paddingScheme"`
+	// Intermediates are the hex encoded intermediate bytes of every block.
+	// Index 0 is the initialization vector and always empty.
+	// Blocks that have not been cracked, yet, are empty.
+	Intermediates []string `json:"intermediates"`
+	// CurrentBlock is the index of the block that is cracked.
+	// It is 0, if all blocks have been cracked.
+	CurrentBlock int `json:"currentBlock"`
+	// CurrentPos is the position of the byte in the current block that is guessed next.
+	// All bytes after it are contained in the intermediate bytes of the current block.
+	CurrentPos int `json:"currentPos"`
+	// QueryCount is the number of queries sent so far, including all previous runs.
+	QueryCount int `json:"queryCount"`
+	// SavedAt is the time the checkpoint has been saved.
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// ******** Public constants ********
+
+// DefaultCheckpointInterval is the default interval in which checkpoints are written.
+const DefaultCheckpointInterval = 10 * time.Second
+
+// ******** Public variables ********
+
+// ErrCheckpointMismatch signals that a checkpoint does not belong to the message that is cracked.
+var ErrCheckpointMismatch = errors.New(`checkpoint does not match the encrypted message, block size or padding scheme`)
+
+// ErrCheckpointNotConfirmed signals that the oracle does not confirm the intermediate bytes of a checkpoint.
+// This happens, if the victim uses a different key than the one the checkpoint has been written for.
+var ErrCheckpointNotConfirmed = errors.New(`checkpoint does not match the oracle, the victim may use a different key`)
+
+// ErrInvalidCheckpoint signals that a checkpoint is inconsistent.
+var ErrInvalidCheckpoint = errors.New(`invalid checkpoint`)
+
+// ******** Private types ********
+
+// checkpointWriter updates a checkpoint while cracking and writes it to a file.
+// All methods of a nil writer do nothing.
+type checkpointWriter struct {
+	path          string
+	interval      time.Duration
+	checkpoint    *Checkpoint
+	baseCount     int
+	limitedOracle *LimitedPaddingOracle
+	nextSaveTime  time.Time
+}
+
+// ******** Public functions ********
+
+// LoadCheckpoint loads a checkpoint from a file.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Checkpoint{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Save saves a checkpoint to a file.
+// The file is replaced atomically, so that an interruption does not leave a broken checkpoint.
+func (c *Checkpoint) Save(path string) error {
+	c.SavedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(c, ``, `  `)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+`.*.tmp`)
+	if err != nil {
+		return err
+	}
+
+	tempPath := tempFile.Name()
+	_, err = tempFile.Write(data)
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// Message returns the encrypted message of the checkpoint.
+func (c *Checkpoint) Message() ([]byte, error) {
+	return hex.DecodeString(c.EncryptedMessage)
+}
+
+// Scheme returns the padding scheme of the checkpoint.
+func (c *Checkpoint) Scheme() (padding.Scheme, error) {
+	result := padding.SchemeByName(c.PaddingScheme)
+	if result == nil {
+		return nil, ErrInvalidCheckpoint
+	}
+
+	return result, nil
+}
+
+// ******** Private functions ********
+
+// newCheckpoint creates a new checkpoint for an encrypted message before cracking starts.
+func newCheckpoint(encryptedMessage []byte, blockSize int, scheme padding.Scheme) *Checkpoint {
+	blockCount := len(encryptedMessage) / blockSize
+	return &Checkpoint{
+		EncryptedMessage: hex.EncodeToString(encryptedMessage),
+		BlockSize:        blockSize,
+		PaddingScheme:    scheme.Name(),
+		Intermediates:    make([]string, blockCount),
+		CurrentBlock:     blockCount - 1,
+		CurrentPos:       blockSize - 1,
+	}
+}
+
+// checkMatch checks whether a checkpoint belongs to an encrypted message, a block size and a padding scheme
+// and whether it is consistent.
+func (c *Checkpoint) checkMatch(encryptedMessage []byte, blockSize int, scheme padding.Scheme) error {
+	checkpointMessage, err := c.Message()
+	if err != nil {
+		return ErrInvalidCheckpoint
+	}
+
+	if !slices.Equal(checkpointMessage, encryptedMessage) ||
+		c.BlockSize != blockSize ||
+		c.PaddingScheme != scheme.Name() {
+		return ErrCheckpointMismatch
+	}
+
+	if len(c.Intermediates) != len(encryptedMessage)/blockSize ||
+		c.CurrentBlock < 0 ||
+		c.CurrentBlock >= len(c.Intermediates) ||
+		c.CurrentPos < 0 ||
+		c.CurrentPos >= blockSize {
+		return ErrInvalidCheckpoint
+	}
+
+	// All blocks after the current one have been cracked, unless they have not been selected.
+	// The current block may have been started.
+	for i := max(c.CurrentBlock, 1); i < len(c.Intermediates); i++ {
+		if len(c.Intermediates[i]) == 0 {
+			continue
+		}
+
+		intermediate, err := hex.DecodeString(c.Intermediates[i])
+		if err != nil || len(intermediate) != blockSize {
+			return ErrInvalidCheckpoint
+		}
+	}
+
+	return nil
+}
+
+// confirm checks with one valid padding query per block whether the oracle confirms
+// the intermediate bytes of a checkpoint.
+// The previous block is modified so that the recovered bytes of a block decrypt to a padding.
+// A negative answer is asked again, so that a noisy oracle does not reject a valid checkpoint.
+// It returns the number of queries.
+func (c *Checkpoint) confirm(oracle oracle.PaddingOracle, encryptedMessage []byte, scheme padding.Scheme) (int, error) {
+	count := 0
+	blockSize := c.BlockSize
+	for blockIndex := max(c.CurrentBlock, 1); blockIndex < len(c.Intermediates); blockIndex++ {
+		intermediate := c.intermediate(blockIndex)
+		if intermediate == nil {
+			continue
+		}
+
+		// Only the bytes after the current position of the current block have been recovered.
+		knownLength := blockSize
+		if blockIndex == c.CurrentBlock {
+			knownLength = blockSize - 1 - c.CurrentPos
+		}
+
+		if knownLength == 0 {
+			continue
+		}
+
+		start := blockIndex * blockSize
+		query := slices.Clone(encryptedMessage[start-blockSize : start+blockSize])
+		tail := scheme.Tail(knownLength)
+		for i, b := range tail {
+			pos := blockSize - knownLength + i
+			query[pos] = intermediate[pos] ^ b
+		}
+
+		confirmed := false
+		for attempt := 0; attempt < maxScansPerByte && !confirmed; attempt++ {
+			var err error
+			confirmed, err = oracle.HasValidPadding(query)
+			count++
+			if err != nil {
+				return count, err
+			}
+		}
+
+		if !confirmed {
+			return count, ErrCheckpointNotConfirmed
+		}
+	}
+
+	return count, nil
+}
+
+// intermediate returns the intermediate bytes of a block.
+// It returns nil, if the block has not been cracked, yet.
+func (c *Checkpoint) intermediate(blockIndex int) []byte {
+	result, err := hex.DecodeString(c.Intermediates[blockIndex])
+	if err != nil || len(result) != c.BlockSize {
+		return nil
+	}
+
+	return result
+}
+
+// newCheckpointWriter creates a new checkpoint writer.
+// It returns nil, if no checkpoint file is specified.
+func newCheckpointWriter(checkpoint *Checkpoint, options Options, limitedOracle *LimitedPaddingOracle) *checkpointWriter {
+	if len(options.CheckpointPath) == 0 {
+		return nil
+	}
+
+	interval := options.CheckpointInterval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	return &checkpointWriter{
+		path:          options.CheckpointPath,
+		interval:      interval,
+		checkpoint:    checkpoint,
+		baseCount:     checkpoint.QueryCount,
+		limitedOracle: limitedOracle,
+		nextSaveTime:  time.Now().Add(interval),
+	}
+}
+
+// update records the progress of cracking and writes the checkpoint, if the interval has elapsed.
+// crackedBlock contains the clear bytes of the block from position pos + 1 on.
+func (w *checkpointWriter) update(blockIndex int, pos int, previousOriginalBlock []byte, crackedBlock []byte) error {
+	if w == nil {
+		return nil
+	}
+
+	intermediate := make([]byte, len(crackedBlock))
+	for i := pos + 1; i < len(crackedBlock); i++ {
+		intermediate[i] = crackedBlock[i] ^ previousOriginalBlock[i]
+	}
+
+	w.checkpoint.Intermediates[blockIndex] = hex.EncodeToString(intermediate)
+
+	// A finished block continues with the last byte of the previous block.
+	if pos < 0 {
+		blockIndex--
+		pos = w.checkpoint.BlockSize - 1
+	}
+
+	w.checkpoint.CurrentBlock = blockIndex
+	w.checkpoint.CurrentPos = pos
+
+	if time.Now().Before(w.nextSaveTime) {
+		return nil
+	}
+
+	return w.save()
+}
+
+// save writes the checkpoint.
+func (w *checkpointWriter) save() error {
+	if w == nil {
+		return nil
+	}
+
+	w.checkpoint.QueryCount = w.baseCount + w.limitedOracle.QueryCount()
+	w.nextSaveTime = time.Now().Add(w.interval)
+
+	return w.checkpoint.Save(w.path)
+}
@@ -0,0 +1,212 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the events that report the progress of cracking.
+//
+// The cracker does not print anything. Instead, it sends events to the observers of the [Options],
+// which may print them, show them in a user interface or write them to a report.
+// The observers are called synchronously by the cracker, so they should return quickly.
+
+package attack
+
+import (
+	"padora/padding"
+)
+
+// ******** Public types ********
+
+// EventKind is the kind of an [Event].
+type EventKind int
+
+// Event is an event that reports the progress of cracking.
+type Event struct {
+	// Kind is the kind of the event.
+	Kind EventKind
+	// QueryCount is the number of queries sent so far, including the detection and previous runs.
+	QueryCount int
+	// BlockSize is the block size.
+	// It is only set for [EventDetected].
+	BlockSize int
+	// Scheme is the padding scheme.
+	// It is only set for [EventDetected].
+	Scheme padding.Scheme
+	// Block is the index of the block in the encrypted message. Index 0 is the initialization vector.
+	// It is not set for [EventDetected] and [EventQueryCount].
+	Block int
+	// Position is the position of the byte in the block.
+	// It is only set for [EventByteRecovered] and [EventFalsePositiveRejected].
+	Position int
+	// Value is the clear byte that has been recovered, or the guess that has been rejected.
+	// It is only set for [EventByteRecovered] and [EventFalsePositiveRejected].
+	Value byte
+}
+
+// Observer receives the events of cracking.
+type Observer interface {
+	// Notify is called for every event.
+	Notify(event Event)
+}
+
+// ObserverFunc is a function that is an [Observer].
+type ObserverFunc func(event Event)
+
+// ******** Public constants ********
+
+// Kinds of events.
+const (
+	// EventDetected is sent when the block size and the padding scheme have been detected.
+	EventDetected EventKind = iota
+	// EventBlockStarted is sent when cracking of a block starts.
+	EventBlockStarted
+	// EventByteRecovered is sent when a byte has been recovered.
+	EventByteRecovered
+	// EventFalsePositiveRejected is sent when a guess that led to a valid padding has been rejected.
+	// This happens if the padding was valid by accident, or if the following byte could not be solved with it.
+	EventFalsePositiveRejected
+	// EventBlockFinished is sent when a block has been cracked.
+	EventBlockFinished
+	// EventQueryCount is sent every time another [QueryCountStep] queries have been sent.
+	EventQueryCount
+)
+
+// QueryCountStep is the number of queries after which another [EventQueryCount] is sent.
+const QueryCountStep = 100_000
+
+// ******** Private types ********
+
+// eventSender sends the events of cracking to the observers.
+// All methods of a nil sender do nothing.
+type eventSender struct {
+	observers          []Observer
+	baseCount          int
+	limitedOracle      *LimitedPaddingOracle
+	block              int
+	nextQueryCountSend int
+}
+
+// ******** Private variables ********
+
+// eventKindNames are the names of the event kinds.
+var eventKindNames = []string{
+	`detected`,
+	`block started`,
+	`byte recovered`,
+	`false positive rejected`,
+	`block finished`,
+	`query count`,
+}
+
+// ******** Public functions ********
+
+// Notify calls the function.
+func (f ObserverFunc) Notify(event Event) {
+	f(event)
+}
+
+// String returns the name of an event kind.
+func (k EventKind) String() string {
+	if k < 0 || int(k) >= len(eventKindNames) {
+		return `unknown`
+	}
+
+	return eventKindNames[k]
+}
+
+// ******** Private functions ********
+
+// newEventSender creates a new event sender.
+// baseCount is the number of queries that have been sent before the limited oracle has been created.
+// It returns nil, if there are no observers.
+func newEventSender(observers []Observer, baseCount int, limitedOracle *LimitedPaddingOracle) *eventSender {
+	if len(observers) == 0 {
+		return nil
+	}
+
+	return &eventSender{
+		observers:          observers,
+		baseCount:          baseCount,
+		limitedOracle:      limitedOracle,
+		nextQueryCountSend: (baseCount/QueryCountStep + 1) * QueryCountStep,
+	}
+}
+
+// blockStarted sends an [EventBlockStarted] and makes the block the current one.
+func (s *eventSender) blockStarted(block int) {
+	if s == nil {
+		return
+	}
+
+	s.block = block
+	s.send(Event{Kind: EventBlockStarted, Block: block})
+}
+
+// byteRecovered sends an [EventByteRecovered] for the current block.
+func (s *eventSender) byteRecovered(pos int, value byte) {
+	if s == nil {
+		return
+	}
+
+	s.send(Event{Kind: EventByteRecovered, Block: s.block, Position: pos, Value: value})
+}
+
+// falsePositiveRejected sends an [EventFalsePositiveRejected] for the current block.
+func (s *eventSender) falsePositiveRejected(pos int, guess byte) {
+	if s == nil {
+		return
+	}
+
+	s.send(Event{Kind: EventFalsePositiveRejected, Block: s.block, Position: pos, Value: guess})
+}
+
+// blockFinished sends an [EventBlockFinished] for the current block.
+func (s *eventSender) blockFinished() {
+	if s == nil {
+		return
+	}
+
+	s.send(Event{Kind: EventBlockFinished, Block: s.block})
+}
+
+// send sends an event to all observers and, if another step of queries has been sent, an [EventQueryCount] before it.
+func (s *eventSender) send(event Event) {
+	event.QueryCount = s.baseCount + s.limitedOracle.QueryCount()
+
+	if event.QueryCount >= s.nextQueryCountSend {
+		s.nextQueryCountSend = (event.QueryCount/QueryCountStep + 1) * QueryCountStep
+		sendEvent(s.observers, Event{Kind: EventQueryCount, QueryCount: event.QueryCount})
+	}
+
+	sendEvent(s.observers, event)
+}
+
+// sendEvent sends an event to all observers.
+func sendEvent(observers []Observer, event Event) {
+	for _, observer := range observers {
+		observer.Notify(event)
+	}
+}
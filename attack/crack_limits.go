@@ -0,0 +1,310 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.4.3
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add checkpoint options.
+//    2026-10-18: V2.0.0: Moved to package attack.
+//    2026-10-18: V2.1.0: Add observers.
+//    2026-10-18: V2.2.0: Add block selection and known prefix.
+//    2026-10-18: V2.3.0: Add known plaintext.
+//    2026-10-18: V2.4.0: Add batch size and ask several messages at once.
+//    2026-10-18: V2.4.1: Tell that known bytes are verified with two queries.
+//    2026-10-18: V2.4.2: Tell that a query timeout needs a backend without state.
+//    2026-10-18: V2.4.3: Document that the in-process victim keeps state.
+//
+
+// This file contains the limits for cracking: a query budget, a rate limit,
+// a timeout for every query and the cancellation by a context.
+//
+// The limits are enforced by a padding oracle that wraps the one the cracker asks.
+// If cracking is stopped by a limit, the cracker returns a [StoppedError]
+// together with the bytes it has recovered so far.
+
+package attack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"padora/numberformat"
+	"padora/oracle"
+	"slices"
+	"time"
+)
+
+// ******** Public types ********
+
+// Options contains the limits for cracking, the checkpoint options, the observers, the selection of the blocks,
+// the known plaintext and the batch size.
+// A zero value means that there is no limit.
+type Options struct {
+	// MaxQueries is the maximum number of queries.
+	MaxQueries int
+	// QueriesPerSecond is the maximum number of queries per second.
+	QueriesPerSecond float64
+	// QueryTimeout is the maximum time a query may need.
+	// The backend keeps running after a timeout, so it must not keep state between queries,
+	// like a connection, a persistent process or the global cipher of the in-process victim,
+	// as the late answer would be taken for the next query or the state would be used concurrently.
+	QueryTimeout time.Duration
+	// CheckpointPath is the path of the file the checkpoints are written to.
+	// If it is empty, no checkpoints are written.
+	CheckpointPath string
+	// CheckpointInterval is the interval in which checkpoints are written.
+	// If it is 0, a default interval is used.
+	CheckpointInterval time.Duration
+	// Resume is the checkpoint to resume cracking from.
+	// If it is nil, cracking starts from the beginning.
+	Resume *Checkpoint
+	// Observers receive the events of cracking.
+	Observers []Observer
+	// Blocks are the indexes of the blocks that are cracked.
+	// Index 0 is the initialization vector, so the first encrypted block has index 1.
+	// If it is empty, all blocks are cracked.
+	Blocks []int
+	// KnownPrefix is the known beginning of the clear message.
+	// Cracking stops as soon as only the known prefix is left.
+	KnownPrefix []byte
+	// KnownPlaintext contains bytes of the clear message that are known.
+	// They are verified with a query and a confirming one instead of being guessed.
+	// If it is nil, all bytes are guessed.
+	KnownPlaintext *KnownPlaintext
+	// BatchSize is the number of guesses that are sent at once, if the oracle is an [oracle.BatchPaddingOracle].
+	// Guesses after a valid one may be sent needlessly, but a batch needs fewer round trips.
+	// If it is 0 or 1, the guesses are sent one after the other.
+	BatchSize int
+}
+
+// StoppedError signals that cracking has been stopped before it was finished.
+type StoppedError struct {
+	// Cause is the reason why cracking has been stopped,
+	// e.g. [ErrQueryBudgetExhausted], [oracle.ErrQueryTimeout] or [context.Canceled].
+	Cause error
+	// QueryCount is the number of queries that were sent.
+	QueryCount int
+	// RecoveredFrom is the index of the first recovered byte of the padded message.
+	// All bytes from this index to the end have been recovered, except the ones of blocks that have not been selected.
+	RecoveredFrom int
+	// PaddedLength is the length of the padded message.
+	PaddedLength int
+}
+
+// LimitedPaddingOracle is a [oracle.BatchPaddingOracle] that enforces the limits of [Options].
+type LimitedPaddingOracle struct {
+	ctx           context.Context
+	backend       oracle.PaddingOracle
+	options       Options
+	queryCount    int
+	nextQueryTime time.Time
+}
+
+// ******** Public variables ********
+
+// ErrQueryBudgetExhausted signals that the maximum number of queries has been reached.
+var ErrQueryBudgetExhausted = errors.New(`query budget exhausted`)
+
+// ******** Public functions ********
+
+// Error returns the error message of a [StoppedError].
+func (e *StoppedError) Error() string {
+	return fmt.Sprintf("cracking stopped after %s queries with %s of %s bytes recovered: %v",
+		numberformat.FormatInt(e.QueryCount),
+		numberformat.FormatInt(e.PaddedLength-e.RecoveredFrom),
+		numberformat.FormatInt(e.PaddedLength),
+		e.Cause)
+}
+
+// Unwrap returns the cause of a [StoppedError].
+func (e *StoppedError) Unwrap() error {
+	return e.Cause
+}
+
+// NewLimitedPaddingOracle creates a new [LimitedPaddingOracle].
+func NewLimitedPaddingOracle(ctx context.Context, backend oracle.PaddingOracle, options Options) *LimitedPaddingOracle {
+	return &LimitedPaddingOracle{
+		ctx:     ctx,
+		backend: backend,
+		options: options,
+	}
+}
+
+// HasValidPadding asks the backend, if no limit is reached.
+func (o *LimitedPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	if err := o.ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if o.options.MaxQueries > 0 && o.queryCount >= o.options.MaxQueries {
+		return false, ErrQueryBudgetExhausted
+	}
+
+	if err := o.waitForRateLimit(); err != nil {
+		return false, err
+	}
+
+	var isValid bool
+	var err error
+	if o.options.QueryTimeout <= 0 {
+		isValid, err = o.backend.HasValidPadding(encryptedMessage)
+	} else {
+		isValid, err = o.queryWithTimeout(encryptedMessage)
+	}
+
+	// A query that a wrapped limited oracle refused has not been sent.
+	if !errors.Is(err, ErrQueryBudgetExhausted) && !errors.Is(err, context.Canceled) {
+		o.queryCount++
+	}
+
+	return isValid, err
+}
+
+// HasValidPaddings asks the backend for several encrypted messages at once, if no limit is reached.
+// If the backend can not answer several messages at once or there is a query timeout,
+// the messages are sent one after the other.
+// If the query budget does not suffice for all messages, only the first ones are sent.
+func (o *LimitedPaddingOracle) HasValidPaddings(encryptedMessages [][]byte) ([]bool, error) {
+	batchOracle, isBatchOracle := o.backend.(oracle.BatchPaddingOracle)
+	if !isBatchOracle || o.options.QueryTimeout > 0 {
+		return askOneByOne(o, encryptedMessages)
+	}
+
+	if err := o.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if o.options.MaxQueries > 0 {
+		remainingQueries := o.options.MaxQueries - o.queryCount
+		if remainingQueries <= 0 {
+			return nil, ErrQueryBudgetExhausted
+		}
+
+		encryptedMessages = encryptedMessages[:min(len(encryptedMessages), remainingQueries)]
+	}
+
+	for range encryptedMessages {
+		if err := o.waitForRateLimit(); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := batchOracle.HasValidPaddings(encryptedMessages)
+	o.queryCount += len(result)
+
+	return result, err
+}
+
+// QueryCount returns the number of queries sent to the backend.
+func (o *LimitedPaddingOracle) QueryCount() int {
+	return o.queryCount
+}
+
+// ******** Private functions ********
+
+// waitForRateLimit waits until the next query may be sent.
+func (o *LimitedPaddingOracle) waitForRateLimit() error {
+	if o.options.QueriesPerSecond <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(float64(time.Second) / o.options.QueriesPerSecond)
+
+	now := time.Now()
+	if o.nextQueryTime.After(now) {
+		timer := time.NewTimer(o.nextQueryTime.Sub(now))
+		select {
+		case <-timer.C:
+
+		case <-o.ctx.Done():
+			timer.Stop()
+			return o.ctx.Err()
+		}
+	}
+
+	// The next query is due one interval after this one was due, so that late timers do not lower the rate.
+	// However, a query that is more than one interval late must not lead to a burst of queries.
+	earliestDue := now.Add(-interval)
+	if o.nextQueryTime.Before(earliestDue) {
+		o.nextQueryTime = earliestDue
+	}
+
+	o.nextQueryTime = o.nextQueryTime.Add(interval)
+
+	return nil
+}
+
+// queryWithTimeout asks the backend and gives up, if it does not answer in time.
+// The backend keeps running after a timeout, so it gets its own copy of the message.
+func (o *LimitedPaddingOracle) queryWithTimeout(encryptedMessage []byte) (bool, error) {
+	type answer struct {
+		isValid bool
+		err     error
+	}
+
+	ctx, cancel := context.WithTimeout(o.ctx, o.options.QueryTimeout)
+	defer cancel()
+
+	answers := make(chan answer, 1)
+	message := slices.Clone(encryptedMessage)
+	go func() {
+		isValid, err := o.backend.HasValidPadding(message)
+		answers <- answer{isValid: isValid, err: err}
+	}()
+
+	select {
+	case a := <-answers:
+		return a.isValid, a.err
+
+	case <-ctx.Done():
+		if err := o.ctx.Err(); err != nil {
+			return false, err
+		}
+
+		return false, oracle.ErrQueryTimeout
+	}
+}
+
+// askOneByOne asks a padding oracle for one encrypted message after the other.
+// If an error occurs, the answers for the messages before it are returned together with the error.
+func askOneByOne(paddingOracle oracle.PaddingOracle, encryptedMessages [][]byte) ([]bool, error) {
+	result := make([]bool, 0, len(encryptedMessages))
+	for _, encryptedMessage := range encryptedMessages {
+		isValid, err := paddingOracle.HasValidPadding(encryptedMessage)
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, isValid)
+	}
+
+	return result, nil
+}
+
+// isStopCause checks whether an error is caused by a limit of cracking.
+func isStopCause(err error) bool {
+	return errors.Is(err, ErrQueryBudgetExhausted) ||
+		errors.Is(err, oracle.ErrQueryTimeout) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
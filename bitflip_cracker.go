@@ -0,0 +1,79 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.0.1: Describe the principle without a link to an unexported function.
+//
+
+// This file contains the functions that perform a bit-flipping attack on a profile
+// that is encrypted with CBC, but not authenticated.
+//
+// In CBC mode the previous encrypted block is XOR-ed into the decrypted block.
+// So flipping a bit in the previous encrypted block flips the same bit in the decrypted block.
+// This is the same principle the padding oracle attack relies on to force a valid padding.
+// The attacker puts placeholders for ';' and '=' in its user data and flips them
+// into the forbidden characters. The previous block is decrypted to garbage,
+// which does not matter, as it only contains filler user data.
+
+package main
+
+import (
+	"strings"
+)
+
+// ******** Private constants ********
+
+// bitflipPlaceholder is the user data that is turned into ";admin=true" by flipping bits.
+// The characters ':' and '<' differ only in the lowest bit from ';' and '='.
+const bitflipPlaceholder = `:admin<true`
+
+// bitflipTarget is the text that the placeholder is turned into.
+const bitflipTarget = `;admin=true`
+
+// bitflipFiller is the character the user data is filled with.
+const bitflipFiller = `A`
+
+// ******** Public functions ********
+
+// ForgeAdminProfile lets the victim encrypt a profile with prepared user data and flips bits
+// in the encrypted profile, so that it contains "admin=true".
+func ForgeAdminProfile(blockSize int) []byte {
+	// 1. Fill up the block with the prefix, so that the user data starts at a block boundary.
+	//    Then add a whole block that will be decrypted to garbage, followed by the placeholder.
+	fillerLength := (blockSize - len(ProfilePrefix)%blockSize) % blockSize
+	userData := strings.Repeat(bitflipFiller, fillerLength+blockSize) + bitflipPlaceholder
+
+	encryptedProfile := EncryptProfile(userData, blockSize)
+
+	// 2. Flip the bits in the block before the placeholder.
+	//    The encrypted profile starts with the initialization vector, so the block before the placeholder
+	//    is at the same position in the encrypted profile as the placeholder in the clear profile.
+	placeholderStart := len(ProfilePrefix) + fillerLength + blockSize
+	for i := 0; i < len(bitflipPlaceholder); i++ {
+		encryptedProfile[placeholderStart+i] ^= bitflipPlaceholder[i] ^ bitflipTarget[i]
+	}
+
+	return encryptedProfile
+}
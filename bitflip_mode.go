@@ -0,0 +1,68 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode that demonstrates the bit-flipping attack.
+
+package main
+
+import (
+	"fmt"
+)
+
+// ******** Public functions ********
+
+// RunBitflip tries to become an administrator, first with an honest injection and then by flipping bits.
+func RunBitflip() {
+	// 1. Try to inject "admin=true" directly. This does not work, as ';' and '=' are escaped.
+	fmt.Println()
+	injectedProfile := EncryptProfile(bitflipTarget, aesBlockSize)
+	showAdminCheck(`Injected profile`, injectedProfile)
+
+	// 2. Flip bits in the encrypted profile.
+	//    Note, that the key is *not* known to the main program!
+	forgedProfile := ForgeAdminProfile(aesBlockSize)
+	showAdminCheck(`Forged profile`, forgedProfile)
+}
+
+// ******** Private functions ********
+
+// showAdminCheck lets the victim check whether an encrypted profile is an administrator's one and shows the result.
+func showAdminCheck(name string, encryptedProfile []byte) {
+	isAdmin, err := IsAdminProfile(encryptedProfile, aesBlockSize)
+
+	switch {
+	case err != nil:
+		fmt.Printf("!!!! %s is rejected: %v !!!!\n", name, err)
+
+	case isAdmin:
+		fmt.Printf(">>>> %s is an administrator's profile! <<<<\n", name)
+
+	default:
+		fmt.Printf("%s is not an administrator's profile\n", name)
+	}
+}
@@ -0,0 +1,269 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the cracker functions that perform Bleichenbacher's attack (1998)
+// on RSA with PKCS#1 v1.5 encryption padding.
+//
+// RSA is malleable: If c is the encryption of m, then c * s^e mod n is the encryption of m * s mod n.
+// If the victim tells whether m * s mod n is PKCS#1 v1.5 conforming, i.e. starts with 00 02,
+// the attacker knows that 2B <= m * s mod n < 3B with B = 2^(8(k-2)) and k the length of the modulus in bytes.
+// Each conforming s narrows the set of intervals that m can be in, until only one value remains.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"math/big"
+)
+
+// ******** Private types ********
+
+// interval is a closed interval of big integers.
+type interval struct {
+	a *big.Int
+	b *big.Int
+}
+
+// bleichenbacherOracle asks the victim whether an encrypted message is conforming and counts the questions.
+type bleichenbacherOracle struct {
+	victim    *Pkcs1Victim
+	publicKey *rsa.PublicKey
+	c         *big.Int
+	e         *big.Int
+	k         int
+	count     int
+}
+
+// ******** Public variables ********
+
+// ErrNoPkcs1Message signals that the cracked message has no valid PKCS#1 v1.5 padding.
+var ErrNoPkcs1Message = errors.New(`cracked message has no valid PKCS#1 v1.5 padding`)
+
+// ******** Private variables ********
+
+// bigOne is the big integer 1.
+var bigOne = big.NewInt(1)
+
+// bigTwo is the big integer 2.
+var bigTwo = big.NewInt(2)
+
+// bigThree is the big integer 3.
+var bigThree = big.NewInt(3)
+
+// ******** Public functions ********
+
+// CrackBleichenbacher cracks a message that has been encrypted for a [Pkcs1Victim].
+// It returns the message and the number of decryption calls needed.
+func CrackBleichenbacher(victim *Pkcs1Victim, encryptedMessage []byte) ([]byte, int, error) {
+	publicKey := victim.PublicKey()
+	n := publicKey.N
+	oracle := &bleichenbacherOracle{
+		victim:    victim,
+		publicKey: publicKey,
+		c:         new(big.Int).SetBytes(encryptedMessage),
+		e:         big.NewInt(int64(publicKey.E)),
+		k:         publicKey.Size(),
+	}
+
+	// B = 2^(8(k-2))
+	bigB := new(big.Int).Lsh(bigOne, uint(8*(oracle.k-2)))
+	twoB := new(big.Int).Mul(bigTwo, bigB)
+	threeB := new(big.Int).Mul(bigThree, bigB)
+
+	// Step 1: The encrypted message is already conforming, so no blinding is needed, i.e. s0 = 1.
+	//         m is in [2B, 3B - 1].
+	intervals := []interval{{a: new(big.Int).Set(twoB), b: new(big.Int).Sub(threeB, bigOne)}}
+
+	var s *big.Int
+	for {
+		switch {
+		case s == nil:
+			// Step 2a: Find the smallest s >= n / 3B that is conforming.
+			s = oracle.findConforming(ceilDiv(n, threeB))
+
+		case len(intervals) > 1:
+			// Step 2b: Find the next s that is conforming.
+			s = oracle.findConforming(new(big.Int).Add(s, bigOne))
+
+		default:
+			// Step 2c: Only one interval is left. Search for s with a growing r.
+			s = oracle.searchWithOneInterval(intervals[0], s, n, twoB, threeB)
+		}
+
+		// Step 3: Narrow the intervals.
+		intervals = narrowIntervals(intervals, s, n, twoB, threeB)
+
+		// Step 4: If there is only one interval with one element, this is the message.
+		if len(intervals) == 1 && intervals[0].a.Cmp(intervals[0].b) == 0 {
+			break
+		}
+	}
+
+	message, err := pkcs1Unpad(intervals[0].a.FillBytes(make([]byte, oracle.k)))
+
+	return message, oracle.count, err
+}
+
+// ******** Private functions ********
+
+// isConforming asks the victim whether the encryption of m * s is conforming.
+func (o *bleichenbacherOracle) isConforming(s *big.Int) bool {
+	o.count++
+
+	// c' = c * s^e mod n
+	modifiedC := new(big.Int).Exp(s, o.e, o.publicKey.N)
+	modifiedC.Mul(modifiedC, o.c)
+	modifiedC.Mod(modifiedC, o.publicKey.N)
+
+	return o.victim.Decrypt(modifiedC.FillBytes(make([]byte, o.k))) == nil
+}
+
+// findConforming finds the smallest s >= start that is conforming.
+func (o *bleichenbacherOracle) findConforming(start *big.Int) *big.Int {
+	s := new(big.Int).Set(start)
+	for !o.isConforming(s) {
+		s.Add(s, bigOne)
+	}
+
+	return s
+}
+
+// searchWithOneInterval finds a conforming s, if there is only one interval [a, b] left.
+func (o *bleichenbacherOracle) searchWithOneInterval(
+	iv interval,
+	previousS *big.Int,
+	n *big.Int,
+	twoB *big.Int,
+	threeB *big.Int) *big.Int {
+	// r >= 2 * (b * s - 2B) / n
+	r := new(big.Int).Mul(iv.b, previousS)
+	r.Sub(r, twoB)
+	r.Mul(r, bigTwo)
+	r = ceilDiv(r, n)
+
+	rn := new(big.Int)
+	for {
+		rn.Mul(r, n)
+
+		// (2B + r * n) / b <= s < (3B + r * n) / a
+		s := ceilDiv(new(big.Int).Add(twoB, rn), iv.b)
+		sMax := ceilDiv(new(big.Int).Add(threeB, rn), iv.a)
+		for ; s.Cmp(sMax) < 0; s.Add(s, bigOne) {
+			if o.isConforming(s) {
+				return s
+			}
+		}
+
+		r.Add(r, bigOne)
+	}
+}
+
+// narrowIntervals calculates the intervals m can be in, after s has been found to be conforming.
+func narrowIntervals(intervals []interval, s *big.Int, n *big.Int, twoB *big.Int, threeB *big.Int) []interval {
+	result := make([]interval, 0, len(intervals))
+
+	threeBMinusOne := new(big.Int).Sub(threeB, bigOne)
+	for _, iv := range intervals {
+		// (a * s - 3B + 1) / n <= r <= (b * s - 2B) / n
+		rMin := new(big.Int).Mul(iv.a, s)
+		rMin.Sub(rMin, threeBMinusOne)
+		rMin = ceilDiv(rMin, n)
+
+		rMax := new(big.Int).Mul(iv.b, s)
+		rMax.Sub(rMax, twoB)
+		rMax.Div(rMax, n)
+
+		rn := new(big.Int)
+		for r := rMin; r.Cmp(rMax) <= 0; r.Add(r, bigOne) {
+			rn.Mul(r, n)
+
+			// a' = max(a, (2B + r * n) / s), b' = min(b, (3B - 1 + r * n) / s)
+			newA := ceilDiv(new(big.Int).Add(twoB, rn), s)
+			if newA.Cmp(iv.a) < 0 {
+				newA.Set(iv.a)
+			}
+
+			newB := new(big.Int).Add(threeBMinusOne, rn)
+			newB.Div(newB, s)
+			if newB.Cmp(iv.b) > 0 {
+				newB.Set(iv.b)
+			}
+
+			if newA.Cmp(newB) <= 0 {
+				result = addInterval(result, interval{a: newA, b: newB})
+			}
+		}
+	}
+
+	return result
+}
+
+// addInterval adds an interval to a list of intervals and merges it with an overlapping one.
+func addInterval(intervals []interval, newInterval interval) []interval {
+	for i, iv := range intervals {
+		if newInterval.a.Cmp(iv.b) <= 0 && newInterval.b.Cmp(iv.a) >= 0 {
+			if newInterval.a.Cmp(iv.a) < 0 {
+				intervals[i].a = newInterval.a
+			}
+
+			if newInterval.b.Cmp(iv.b) > 0 {
+				intervals[i].b = newInterval.b
+			}
+
+			return intervals
+		}
+	}
+
+	return append(intervals, newInterval)
+}
+
+// ceilDiv returns x / y rounded up for positive y.
+func ceilDiv(x *big.Int, y *big.Int) *big.Int {
+	result, remainder := new(big.Int).DivMod(x, y, new(big.Int))
+	if remainder.Sign() != 0 {
+		result.Add(result, bigOne)
+	}
+
+	return result
+}
+
+// pkcs1Unpad removes the PKCS#1 v1.5 encryption padding from an encoded message.
+func pkcs1Unpad(encodedMessage []byte) ([]byte, error) {
+	if encodedMessage[0] != 0 || encodedMessage[1] != 2 {
+		return nil, ErrNoPkcs1Message
+	}
+
+	separatorIndex := bytes.IndexByte(encodedMessage[2:], 0)
+	if separatorIndex < 0 {
+		return nil, ErrNoPkcs1Message
+	}
+
+	return encodedMessage[2+separatorIndex+1:], nil
+}
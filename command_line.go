@@ -19,11 +19,33 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.1.0
+// Version: 2.17.0
 //
 // Change history:
 //    2024-06-23: V1.0.0: Created.
 //    2024-08-29: V1.1.0: Print used number of blocks.
+//    2026-10-18: V2.0.0: Use flags for mode and victim options.
+//    2026-10-18: V2.1.0: Add probe mode.
+//    2026-10-18: V2.2.0: Add padding scheme option.
+//    2026-10-18: V2.3.0: Add noisy oracle options.
+//    2026-10-18: V2.4.0: Add cracking limits.
+//    2026-10-18: V2.5.0: Add checkpoint options.
+//    2026-10-18: V2.6.0: Add record and replay options.
+//    2026-10-18: V2.7.0: Add seed, key and initialization vector options.
+//    2026-10-18: V2.8.0: Use the library packages.
+//    2026-10-18: V2.9.0: Add intermediate mode and options.
+//    2026-10-18: V2.10.0: Add block selection and known prefix options.
+//    2026-10-18: V2.11.0: Add known plaintext template option.
+//    2026-10-18: V2.12.0: Add campaign mode and tokens option.
+//    2026-10-18: V2.13.0: Add cipher option.
+//    2026-10-18: V2.14.0: Add decrypt mode and external command options.
+//    2026-10-18: V2.15.0: Add server mode and TCP options.
+//    2026-10-18: V2.15.1: Tell that resuming needs the key.
+//    2026-10-18: V2.16.0: Reject a query timeout with a TCP connection or a persistent command.
+//    2026-10-18: V2.16.1: Tell which modes do not depend on the seed.
+//    2026-10-18: V2.16.2: Allow a query timeout only with a command that is not persistent.
+//    2026-10-18: V2.16.3: Split the command like a shell does.
+//    2026-10-18: V2.17.0: Accept keys of the toy ciphers and add the usage to the options.
 //
 
 // This file contains the functions to process the command line arguments.
@@ -31,10 +53,133 @@
 package main
 
 import (
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"padora/attack"
 	"padora/numberformat"
+	"padora/oracle"
+	"padora/padding"
+	"padora/victim"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ******** Public types ********
+
+// Options contains the options specified on the command line.
+type Options struct {
+	// Mode is the mode of operation.
+	Mode string
+	// NumBlocks is the number of blocks of the secret message.
+	NumBlocks int
+	// ConstantTime specifies whether the victim uses constant-time unpadding.
+	ConstantTime bool
+	// Padding is the name of the padding scheme the victim uses.
+	Padding string
+	// FalsePositiveRate is the probability that the victim reports an invalid padding as valid.
+	FalsePositiveRate float64
+	// FalseNegativeRate is the probability that the victim reports a valid padding as invalid.
+	FalseNegativeRate float64
+	// ErrorRate is the probability that a query of the victim fails.
+	ErrorRate float64
+	// Reliability contains the options that make the answers of a noisy victim reliable.
+	Reliability oracle.ReliabilityOptions
+	// Limits contains the limits for cracking and the checkpoint options.
+	Limits attack.Options
+	// ResumePath is the path of the checkpoint file to resume cracking from.
+	ResumePath string
+	// RecordPath is the path of the transcript file the queries are recorded to.
+	RecordPath string
+	// ReplayPath is the path of the transcript file the queries are replayed from.
+	ReplayPath string
+	// HasSeed specifies whether a seed for the random data has been set.
+	HasSeed bool
+	// Seed is the seed for the random data.
+	Seed int64
+	// Key is the key of the victim, or nil, if it is random.
+	Key []byte
+	// IV is the initialization vector of the victim, or nil, if it is random.
+	IV []byte
+	// Cipher is the name of the block cipher of the victim.
+	Cipher string
+	// IntermediatesPath is the path of the file the intermediate decryption state is written to or read from.
+	IntermediatesPath string
+	// ChosenIV is the initialization vector the clear message is re-derived with, or nil, if it is random.
+	ChosenIV []byte
+	// TokensPath is the path of the file with the hex encoded tokens of the campaign mode, one per line.
+	TokensPath string
+	// Command is the external command that is asked instead of the local victim, or nil.
+	Command []string
+	// Persistent specifies that the external command is started once and answers a line for every query.
+	Persistent bool
+	// InvalidPattern matches the output of the external command for an invalid padding, or is nil.
+	// If it is nil, an exit code of 0 means a valid padding.
+	InvalidPattern *regexp.Regexp
+	// ListenAddress is the address the victim listens on in server mode.
+	ListenAddress string
+	// ConnectAddress is the address of a victim in server mode that is asked instead of the local victim, or empty.
+	ConnectAddress string
+	// Usage prints the usage of the command line.
+	Usage func()
+}
+
+// ******** Public constants ********
+
+// ModeCrack is the mode that cracks a secret message with a padding oracle.
+const ModeCrack = `crack`
+
+// ModeTiming is the mode that measures the timing of the victim's unpad function.
+const ModeTiming = `timing`
+
+// ModeLucky13 is the mode that cracks a secret message with the "Lucky Thirteen" timing attack.
+const ModeLucky13 = `lucky13`
+
+// ModePoodle is the mode that cracks a secret cookie with the POODLE attack.
+const ModePoodle = `poodle`
+
+// ModeBleichenbacher is the mode that cracks an RSA encrypted message with Bleichenbacher's attack.
+const ModeBleichenbacher = `bleichenbacher`
+
+// ModeManger is the mode that cracks an RSA encrypted message with Manger's attack.
+const ModeManger = `manger`
+
+// ModeBitflip is the mode that forges an encrypted profile by flipping bits.
+const ModeBitflip = `bitflip`
+
+// ModeKeyAsIV is the mode that recovers a key that is also used as the initialization vector.
+const ModeKeyAsIV = `keyasiv`
+
+// ModeEcb is the mode that cracks a secret message encrypted in ECB mode byte by byte.
+const ModeEcb = `ecb`
+
+// ModeProbe is the mode that probes an unknown target for a padding oracle.
+const ModeProbe = `probe`
+
+// ModeIntermediate is the mode that shows the intermediate decryption state
+// and re-derives the clear message for a chosen initialization vector.
+const ModeIntermediate = `intermediate`
+
+// ModeCampaign is the mode that cracks many encrypted tokens with a shared padding oracle.
+const ModeCampaign = `campaign`
+
+// ModeDecrypt is the mode in which the victim answers lines with encrypted messages on the standard input,
+// so that it can be attacked as an external command.
+const ModeDecrypt = `decrypt`
+
+// ModeServer is the mode in which the victim answers encrypted messages over TCP in a line protocol.
+const ModeServer = `server`
+
+// Names of the ciphers of the victim.
+const (
+	CipherAes  = `aes`
+	CipherToy4 = `toy4`
+	CipherToy8 = `toy8`
 )
 
 // ******** Private constants ********
@@ -42,27 +187,414 @@ import (
 // errMsgInvalidNoOfBlocks is the error message for an invalid number of blocks.
 const errMsgInvalidNoOfBlocks = "Invalid number of blocks: %d\n"
 
+// errMsgInvalidMode is the error message for an invalid mode.
+const errMsgInvalidMode = "Invalid mode: '%s'\n"
+
+// errMsgInvalidPadding is the error message for an invalid padding scheme.
+const errMsgInvalidPadding = "Invalid padding scheme: '%s'\n"
+
+// errMsgInvalidRate is the error message for an invalid rate.
+const errMsgInvalidRate = "Invalid %s rate: %g\n"
+
+// errMsgConstantTimePadding is the error message for constant-time unpadding with a padding scheme other than PKCS#7.
+const errMsgConstantTimePadding = "Constant-time unpadding is only available for padding scheme '%s'\n"
+
+// errMsgPrefixAndTemplate is the error message for a known prefix together with a known plaintext template.
+const errMsgPrefixAndTemplate = "A known prefix can not be combined with a known plaintext template\n"
+
+// errMsgPersistentPattern is the error message for a persistent command without an invalid pattern.
+const errMsgPersistentPattern = "A persistent command needs an invalid pattern, as it has no exit code for every query\n"
+
+// errMsgTimeoutStateful is the error message for a query timeout with a victim that keeps state between queries.
+const errMsgTimeoutStateful = "A query timeout can only be used with a command that is not persistent, as the victim keeps running after a timeout and would be asked concurrently or take a late answer for the next query\n"
+
+// errMsgInvalidCipher is the error message for an invalid cipher.
+const errMsgInvalidCipher = "Invalid cipher: '%s'\n"
+
+// errMsgCipherMode is the error message for a toy cipher in a mode that only works with AES.
+const errMsgCipherMode = "Cipher '%s' is only available in the modes %s\n"
+
+// errMsgAesKeyLength is the error message for an AES key with an invalid length.
+const errMsgAesKeyLength = "An AES key must have 16, 24 or 32 bytes, not %d\n"
+
+// errMsgIVLength is the error message for an initialization vector that does not have the block size of the cipher.
+const errMsgIVLength = "Initialization vector must have %d bytes for cipher '%s'\n"
+
 // defaultNumBlocks is the default number of blocks for secret message.
 const defaultNumBlocks = 3
 
+// defaultListenAddress is the default address the victim listens on in server mode.
+const defaultListenAddress = `localhost:7007`
+
+// defaultRetries is the default number of retries of a failed query.
+const defaultRetries = 3
+
 // minNumBlocks is the minimum allowed number of blocks.
 const minNumBlocks = 1
 
 // minNumBlocks is the maximum allowed number of blocks.
 const maxNumBlocks = 4_000
 
+// ******** Private variables ********
+
+// validModes contains all valid modes.
+var validModes = []string{
+	ModeCrack,
+	ModeTiming,
+	ModeLucky13,
+	ModePoodle,
+	ModeBleichenbacher,
+	ModeManger,
+	ModeBitflip,
+	ModeKeyAsIV,
+	ModeEcb,
+	ModeProbe,
+	ModeIntermediate,
+	ModeCampaign,
+	ModeDecrypt,
+	ModeServer,
+}
+
+// cipherBlockSizes contains the block size of every cipher.
+var cipherBlockSizes = map[string]int{
+	CipherAes:  aesBlockSize,
+	CipherToy4: 4,
+	CipherToy8: 8,
+}
+
+// validCiphers contains all valid ciphers.
+var validCiphers = []string{
+	CipherAes,
+	CipherToy4,
+	CipherToy8,
+}
+
+// aesKeyLengths contains the allowed lengths of an AES key.
+var aesKeyLengths = []int{16, 24, 32}
+
+// ivLengths contains the allowed lengths of an initialization vector, i.e. the block sizes of all ciphers.
+var ivLengths = slices.Concat([]int{aesBlockSize}, victim.ToyBlockSizes)
+
+// cipherModes contains the modes that are available with every cipher.
+// All other modes are only available with AES.
+var cipherModes = []string{
+	ModeCrack,
+	ModeIntermediate,
+	ModeCampaign,
+	ModeDecrypt,
+	ModeServer,
+}
+
 // ******** Public functions ********
 
-// GetNumBlocks gets the number of blocks to generate from the command line argument.
-func GetNumBlocks() int {
-	var err error
+// GetOptions gets the options from the command line.
+// The number of blocks is the only positional argument.
+func GetOptions() *Options {
+	result := &Options{}
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flagSet.Usage = func() {
+		_, _ = fmt.Fprintf(flagSet.Output(), "Usage: %s [options] [numBlocks]\n\nOptions:\n", flagSet.Name())
+		flagSet.PrintDefaults()
+	}
+	result.Usage = flagSet.Usage
 
-	fmt.Println()
+	flagSet.StringVar(&result.Mode, `mode`, ModeCrack, `mode of operation (`+strings.Join(validModes, `, `)+`)`)
+	flagSet.BoolVar(&result.ConstantTime, `constant-time`, false, `victim uses constant-time unpadding`)
+	flagSet.StringVar(&result.Cipher, `cipher`, CipherAes, `block cipher of the victim (`+strings.Join(validCiphers, `, `)+`)`)
+	flagSet.StringVar(&result.Padding, `padding`, padding.NamePkcs7, `padding scheme of the victim (`+strings.Join(padding.SchemeNames(), `, `)+`)`)
+	flagSet.Float64Var(&result.FalsePositiveRate, `false-positive`, 0, `probability that the victim reports an invalid padding as valid`)
+	flagSet.Float64Var(&result.FalseNegativeRate, `false-negative`, 0, `probability that the victim reports a valid padding as invalid`)
+	flagSet.Float64Var(&result.ErrorRate, `error-rate`, 0, `probability that a query of the victim fails`)
+	flagSet.IntVar(&result.Reliability.MaxRetries, `retries`, defaultRetries, `number of retries of a failed query`)
+	flagSet.IntVar(&result.Reliability.Votes, `votes`, 1, `number of votes per query whose majority is the answer`)
+	flagSet.IntVar(&result.Reliability.ConfirmationMargin, `confirm`, 0, `margin by which valid votes have to outnumber invalid ones to confirm a valid padding`)
+	flagSet.IntVar(&result.Limits.MaxQueries, `max-queries`, 0, `maximum number of queries (0: unlimited)`)
+	flagSet.Float64Var(&result.Limits.QueriesPerSecond, `qps`, 0, `maximum number of queries per second (0: unlimited)`)
+	flagSet.DurationVar(&result.Limits.QueryTimeout, `query-timeout`, 0, `maximum time a query to a command that is not persistent may need (0: unlimited)`)
+	flagSet.StringVar(&result.Limits.CheckpointPath, `checkpoint`, ``, `file the cracking progress is written to`)
+	flagSet.DurationVar(&result.Limits.CheckpointInterval, `checkpoint-interval`, attack.DefaultCheckpointInterval, `interval in which the checkpoint file is written`)
+	flagSet.StringVar(&result.ResumePath, `resume`, ``, `checkpoint file to resume cracking from (needs the key of the checkpointed run: -key)`)
+	flagSet.StringVar(&result.RecordPath, `record`, ``, `transcript file the queries to the victim are recorded to`)
+	flagSet.StringVar(&result.ReplayPath, `replay`, ``, `transcript file the answers of the victim are replayed from`)
+	flagSet.Func(`seed`, `seed for deterministic secret messages, keys and initialization vectors (not for the RSA keys of the bleichenbacher and manger modes)`, func(value string) error {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		result.Seed = seed
+		result.HasSeed = true
+		return err
+	})
+	flagSet.Func(`key`, `hex encoded key of the victim, 16, 24 or 32 bytes for AES and at least 1 byte for a toy cipher`, func(value string) error {
+		var err error
+		result.Key, err = hex.DecodeString(value)
+		if err == nil && len(result.Key) == 0 {
+			err = errors.New(`empty key`)
+		}
+
+		return err
+	})
+	flagSet.Func(`iv`, `hex encoded initialization vector the victim uses for every encryption`, func(value string) error {
+		var err error
+		result.IV, err = parseHexBytes(value, ivLengths...)
+		return err
+	})
+	flagSet.Func(`blocks`, `comma separated indexes of the blocks to crack, 1 is the first block after the initialization vector (default: all)`, func(value string) error {
+		var err error
+		result.Limits.Blocks, err = parseBlockIndexes(value)
+		return err
+	})
+	flagSet.Func(`known-prefix`, `known beginning of the secret message, cracking stops when only it is left`, func(value string) error {
+		result.Limits.KnownPrefix = []byte(value)
+		return nil
+	})
+	flagSet.Func(`template`, `known plaintext template of the beginning of the secret message, '?' is an unknown byte`, func(value string) error {
+		var err error
+		result.Limits.KnownPlaintext, err = attack.ParseTemplate(value)
+		return err
+	})
+	flagSet.StringVar(&result.TokensPath, `tokens`, ``, `file with one hex encoded token per line that is cracked in campaign mode`)
+	flagSet.Func(`command`, `external command that is asked instead of the victim, split into arguments like a shell does, '`+oracle.CommandPlaceholder+`' is replaced by the hex encoded message`, func(value string) error {
+		var err error
+		result.Command, err = splitCommandLine(value)
+		return err
+	})
+	flagSet.BoolVar(&result.Persistent, `persistent`, false, `external command is started once and answers a line for every hex encoded message line`)
+	flagSet.Func(`invalid-pattern`, `regular expression that matches the output of the external command for an invalid padding`, func(value string) error {
+		var err error
+		result.InvalidPattern, err = regexp.Compile(value)
+		return err
+	})
+	flagSet.StringVar(&result.ListenAddress, `listen`, defaultListenAddress, `address the victim listens on in server mode`)
+	flagSet.StringVar(&result.ConnectAddress, `connect`, ``, `address of a victim in server mode that is asked instead of the local victim`)
+	flagSet.IntVar(&result.Limits.BatchSize, `batch`, 1, `number of guesses that are sent at once to a victim in server mode`)
+	flagSet.StringVar(&result.IntermediatesPath, `intermediates`, ``, `file the intermediate decryption state is written to, or read from in intermediate mode`)
+	flagSet.Func(`chosen-iv`, `hex encoded initialization vector the clear message is re-derived with in intermediate mode`, func(value string) error {
+		var err error
+		result.ChosenIV, err = parseHexBytes(value, ivLengths...)
+		return err
+	})
+
+	// With flag.ExitOnError Parse never returns an error.
+	_ = flagSet.Parse(os.Args[1:])
+
+	// In decrypt mode the standard output only contains the answers.
+	if result.Mode != ModeDecrypt {
+		fmt.Println()
+	}
+
+	if !slices.Contains(validModes, result.Mode) {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgInvalidMode, result.Mode)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	if padding.SchemeByName(result.Padding) == nil {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgInvalidPadding, result.Padding)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	checkCipher(flagSet, result)
+
+	checkRate(flagSet, `false positive`, result.FalsePositiveRate)
+	checkRate(flagSet, `false negative`, result.FalseNegativeRate)
+	checkRate(flagSet, `error`, result.ErrorRate)
+
+	if result.ConstantTime && result.Padding != padding.NamePkcs7 {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgConstantTimePadding, padding.NamePkcs7)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	if result.Persistent && result.InvalidPattern == nil {
+		_, _ = fmt.Fprint(os.Stderr, errMsgPersistentPattern)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	if result.Limits.QueryTimeout > 0 && (result.Command == nil || result.Persistent) {
+		_, _ = fmt.Fprint(os.Stderr, errMsgTimeoutStateful)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	if len(result.Limits.KnownPrefix) != 0 && result.Limits.KnownPlaintext != nil {
+		_, _ = fmt.Fprint(os.Stderr, errMsgPrefixAndTemplate)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	result.NumBlocks = defaultNumBlocks
+	if modeUsesBlocks(result.Mode) {
+		result.NumBlocks = getNumBlocks(flagSet.Arg(0))
+	}
+
+	return result
+}
+
+// IsNoisy checks whether the victim answers unreliably.
+func (o *Options) IsNoisy() bool {
+	return o.FalsePositiveRate > 0 || o.FalseNegativeRate > 0 || o.ErrorRate > 0
+}
+
+// ******** Private functions ********
+
+// parseHexBytes parses a hex encoded value and checks whether it has one of the allowed lengths.
+func parseHexBytes(value string, allowedLengths ...int) ([]byte, error) {
+	result, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(allowedLengths, len(result)) {
+		return nil, fmt.Errorf("invalid length %d bytes", len(result))
+	}
+
+	return result, nil
+}
+
+// parseBlockIndexes parses a comma separated list of block indexes.
+func parseBlockIndexes(value string) ([]int, error) {
+	parts := strings.Split(value, `,`)
+	result := make([]int, len(parts))
+	for i, part := range parts {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+
+		if index < 1 {
+			return nil, fmt.Errorf("invalid block index %d", index)
+		}
+
+		result[i] = index
+	}
+
+	return result, nil
+}
+
+// splitCommandLine splits a command line into its arguments like a POSIX shell does, but expands nothing.
+// Single quotes keep all characters, double quotes keep all characters except a backslash
+// before '"', '\', '$' or '`' and a backslash outside of quotes keeps the next character.
+func splitCommandLine(value string) ([]string, error) {
+	var result []string
+	var arg strings.Builder
+	hasArg := false
+	quote := rune(0)
+	isEscaped := false
+	for _, c := range value {
+		switch {
+		case isEscaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", c) {
+				arg.WriteRune('\\')
+			}
+
+			arg.WriteRune(c)
+			isEscaped = false
+
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+
+		case c == '\\':
+			isEscaped = true
+			hasArg = true
+
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+			hasArg = true
+
+		case unicode.IsSpace(c):
+			if hasArg {
+				result = append(result, arg.String())
+				arg.Reset()
+				hasArg = false
+			}
+
+		default:
+			arg.WriteRune(c)
+			hasArg = true
+		}
+	}
+
+	if isEscaped || quote != 0 {
+		return nil, errors.New(`unterminated quote or escape`)
+	}
+
+	if hasArg {
+		result = append(result, arg.String())
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New(`empty command`)
+	}
+
+	return result, nil
+}
+
+// checkCipher checks whether the cipher exists, is available in the mode and has the block size of the initialization vector.
+// It exits, if it does not.
+func checkCipher(flagSet *flag.FlagSet, options *Options) {
+	var errorText string
+	switch {
+	case !slices.Contains(validCiphers, options.Cipher):
+		errorText = fmt.Sprintf(errMsgInvalidCipher, options.Cipher)
+
+	case options.Cipher != CipherAes && !slices.Contains(cipherModes, options.Mode):
+		errorText = fmt.Sprintf(errMsgCipherMode, options.Cipher, strings.Join(cipherModes, `, `))
+
+	case options.Cipher == CipherAes && options.Key != nil && !slices.Contains(aesKeyLengths, len(options.Key)):
+		errorText = fmt.Sprintf(errMsgAesKeyLength, len(options.Key))
+
+	case options.IV != nil && len(options.IV) != cipherBlockSizes[options.Cipher]:
+		errorText = fmt.Sprintf(errMsgIVLength, cipherBlockSizes[options.Cipher], options.Cipher)
+
+	default:
+		return
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, errorText)
+	flagSet.Usage()
+	os.Exit(2)
+}
+
+// checkRate checks whether a rate is a probability less than 1 and exits, if it is not.
+func checkRate(flagSet *flag.FlagSet, name string, rate float64) {
+	if rate < 0 || rate >= 1 {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgInvalidRate, name, rate)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+}
+
+// modeUsesBlocks checks whether a mode uses the number of blocks.
+func modeUsesBlocks(mode string) bool {
+	switch mode {
+	case ModeTiming, ModeBleichenbacher, ModeManger, ModeBitflip, ModeDecrypt, ModeServer:
+		return false
+
+	default:
+		return true
+	}
+}
+
+// getNumBlocks gets the number of blocks to generate from the command line argument.
+func getNumBlocks(arg string) int {
+	var err error
 
 	numBlocks := defaultNumBlocks
 
-	if len(os.Args) > 1 {
-		numBlocks, err = strconv.Atoi(os.Args[1])
+	if len(arg) > 0 {
+		numBlocks, err = strconv.Atoi(arg)
 
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, errMsgInvalidNoOfBlocks, numBlocks)
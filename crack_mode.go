@@ -0,0 +1,419 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.10.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Show progress from the events of the cracker.
+//    2026-10-18: V1.2.0: Write the intermediate state.
+//    2026-10-18: V1.3.0: Report selected blocks and known prefixes.
+//    2026-10-18: V1.4.0: Report the queries saved by a known plaintext template.
+//    2026-10-18: V1.5.0: Use the block size of the victim's cipher.
+//    2026-10-18: V1.6.0: Ask an external command instead of the victim.
+//    2026-10-18: V1.7.0: Ask a victim in server mode over TCP.
+//    2026-10-18: V1.8.0: Show the recovered bytes, if cracking fails.
+//    2026-10-18: V1.9.0: Check the block selection before cracking.
+//    2026-10-18: V1.9.1: Tell that a known byte is verified with two calls.
+//    2026-10-18: V1.10.0: Always retry failed queries to a remote victim.
+//    2026-10-18: V1.10.1: Wait at most the answer timeout for a victim in server mode.
+//
+
+// This file contains the mode that cracks a secret message with a padding oracle.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"padora/attack"
+	"padora/numberformat"
+	"padora/oracle"
+	"padora/padding"
+	"padora/randomsource"
+	"padora/slicehelper"
+	"padora/victim"
+	"slices"
+	"time"
+)
+
+// ******** Private constants ********
+
+// remoteRetryDelay is the time to wait before the first retry of a failed query to a remote victim.
+const remoteRetryDelay = 100 * time.Millisecond
+
+// remoteAnswerTimeout is the maximum time to wait for an answer of a victim in server mode,
+// before the connection is treated as dropped and the query is retried.
+const remoteAnswerTimeout = 10 * time.Second
+
+// ******** Public functions ********
+
+// RunCrack generates a secret message, encrypts it and cracks it with a padding oracle.
+// If the victim is noisy, its answers are made reliable by retrying, voting and confirming.
+// Cracking can be cancelled with Ctrl-C and keeps what has been recovered.
+// If a checkpoint to resume from or a transcript to replay is specified, its encrypted message is cracked instead.
+func RunCrack(options *Options) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	blockSize := victim.BlockSize()
+	var secretMessage []byte
+	var encryptedMessage []byte
+	var transcript *oracle.Transcript
+	var err error
+	switch {
+	case len(options.ReplayPath) != 0:
+		// 2. - 3. Load the encrypted message from the transcript.
+		//         The secret message is not known in this case.
+		transcript, err = oracle.LoadTranscript(options.ReplayPath)
+		if err == nil && transcript.EncryptedMessage == nil {
+			err = oracle.ErrNotInTranscript
+		}
+
+		if err != nil {
+			fmt.Printf("Unable to replay transcript '%s': %v\n", options.ReplayPath, err)
+			return
+		}
+
+		encryptedMessage = transcript.EncryptedMessage
+		fmt.Printf("\nReplaying %s queries from transcript '%s'\n",
+			numberformat.FormatInt(len(transcript.Entries)),
+			options.ReplayPath)
+
+	case len(options.ResumePath) != 0:
+		// 2. - 3. Load the encrypted message from the checkpoint.
+		//         The secret message is not known in this case.
+		checkpoint, err := loadResumeCheckpoint(options)
+		if err != nil {
+			fmt.Printf("Unable to resume from checkpoint '%s': %v\n", options.ResumePath, err)
+			return
+		}
+
+		encryptedMessage, _ = checkpoint.Message()
+		fmt.Printf("\nResuming from checkpoint '%s' at block %d after %s queries\n",
+			options.ResumePath,
+			checkpoint.CurrentBlock,
+			numberformat.FormatInt(checkpoint.QueryCount))
+
+	default:
+		// 2. Generate a secret message with has a length about the number of blocks.
+		//    If a prefix or a template is known, the secret message begins with it.
+		secretMessage = slicehelper.Concat(options.Limits.KnownPrefix,
+			fillTemplate(options.Limits.KnownPlaintext),
+			makeSecretMessage(options.NumBlocks, blockSize))
+		fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+		// 3. Encrypt the secret message.
+		//    Note, that the key is *not* known to the main program!
+		encryptedMessage = victim.PadAndEncrypt(secretMessage, blockSize)
+	}
+
+	// Padded length is encrypted length minus initialization vector length.
+	paddedLength := len(encryptedMessage) - blockSize
+	fmt.Printf("Length of padded encrypted message is %s bytes\n", numberformat.FormatInt(paddedLength))
+
+	// The victim's block size is known here, so an invalid block selection is found without asking the victim.
+	if err := attack.CheckBlockSelection(options.Limits.Blocks, len(encryptedMessage)/blockSize); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// 4. - 6. Crack the message with a padding oracle that asks the victim.
+	var target oracle.PaddingOracle
+	switch {
+	case transcript != nil:
+		target = oracle.NewReplayPaddingOracle(transcript)
+
+	case len(options.ConnectAddress) != 0:
+		tcpOracle, err := oracle.NewTcpPaddingOracle(options.ConnectAddress, oracle.TcpOptions{AnswerTimeout: remoteAnswerTimeout})
+		if err != nil {
+			fmt.Printf("Unable to connect to '%s': %v\n", options.ConnectAddress, err)
+			return
+		}
+
+		defer func() { _ = tcpOracle.Close() }()
+		target = tcpOracle
+
+	case options.Command != nil:
+		commandOracle, err := oracle.NewCommandOracle(options.Command, oracle.CommandOptions{Persistent: options.Persistent})
+		if err != nil {
+			fmt.Printf("Unable to run command '%s': %v\n", options.Command[0], err)
+			return
+		}
+
+		defer func() { _ = commandOracle.Close() }()
+		target = oracle.NewPatternPaddingOracle(commandOracle, options.InvalidPattern, nil)
+
+	case options.IsNoisy():
+		target = victim.NewNoisyPaddingOracle(victim.NewLocalPaddingOracle(blockSize),
+			options.FalsePositiveRate,
+			options.FalseNegativeRate,
+			options.ErrorRate)
+
+	default:
+		target = victim.NewLocalPaddingOracle(blockSize)
+	}
+
+	if len(options.RecordPath) != 0 && transcript == nil {
+		recorder, err := oracle.NewRecordingPaddingOracle(target, options.RecordPath, encryptedMessage)
+		if err != nil {
+			fmt.Printf("Unable to record transcript '%s': %v\n", options.RecordPath, err)
+			return
+		}
+
+		defer func() { _ = recorder.Close() }()
+		target = recorder
+	}
+
+	// A replayed victim may have been noisy, so its answers are made reliable in the same way.
+	// A remote victim may drop the connection or end, so its failed queries are always retried.
+	isRemote := len(options.ConnectAddress) != 0 || options.Command != nil
+	var result *attack.Result
+	if !options.IsNoisy() && transcript == nil && !isRemote {
+		result = crackAndReport(ctx, target, secretMessage, encryptedMessage, blockSize, options.Limits)
+	} else {
+		var reliableOracle *oracle.ReliablePaddingOracle
+		if isRemote {
+			reliableOracle = remoteReliableOracle(target, options)
+		} else {
+			reliableOracle = oracle.NewReliablePaddingOracle(target, options.Reliability)
+		}
+
+		result = crackAndReport(ctx, reliableOracle, secretMessage, encryptedMessage, blockSize, options.Limits)
+		fmt.Printf("The victim has been asked %s times.\n", numberformat.FormatInt(reliableOracle.QueryCount()))
+	}
+
+	if len(options.IntermediatesPath) != 0 && result.State != nil {
+		if err = result.State.Save(options.IntermediatesPath); err != nil {
+			fmt.Printf("Unable to write intermediate state '%s': %v\n", options.IntermediatesPath, err)
+		}
+	}
+}
+
+// ******** Private functions ********
+
+// loadResumeCheckpoint loads the checkpoint to resume from and puts it into the cracking options.
+// If no checkpoint file is specified, the checkpoints are written to the file that is resumed from.
+func loadResumeCheckpoint(options *Options) (*attack.Checkpoint, error) {
+	checkpoint, err := attack.LoadCheckpoint(options.ResumePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = checkpoint.Message(); err != nil {
+		return nil, attack.ErrInvalidCheckpoint
+	}
+
+	options.Limits.Resume = checkpoint
+	if len(options.Limits.CheckpointPath) == 0 {
+		options.Limits.CheckpointPath = options.ResumePath
+	}
+
+	return checkpoint, nil
+}
+
+// remoteReliableOracle makes the answers of a remote victim reliable.
+// Failed queries are retried with a delay, so that a dropped connection or an ended command does not stop cracking.
+func remoteReliableOracle(target oracle.PaddingOracle, options *Options) *oracle.ReliablePaddingOracle {
+	reliability := options.Reliability
+	reliability.RetryDelay = remoteRetryDelay
+
+	return oracle.NewReliablePaddingOracle(target, reliability)
+}
+
+// crackAndReport cracks an encrypted message with a padding oracle, reports the result and returns it.
+func crackAndReport(
+	ctx context.Context,
+	paddingOracle oracle.PaddingOracle,
+	secretMessage []byte,
+	encryptedMessage []byte,
+	blockSize int,
+	limits attack.Options) *attack.Result {
+	paddedLength := len(encryptedMessage) - blockSize
+
+	// 4. Crack the message with a padding oracle.
+	//    Note that the cracker does *not* know the key, the block size or the padding scheme!
+	limits.Observers = append(limits.Observers, attack.ObserverFunc(showProgress))
+	startTime := time.Now()
+	result, err := attack.CrackAutomatically(ctx, paddingOracle, encryptedMessage, limits)
+	elapsedTime := time.Since(startTime)
+	recoveredMessage := result.ClearMessage
+	count := result.QueryCount
+
+	// 5. Check if the message has successfully been cracked.
+	fmt.Println()
+	var stoppedError *attack.StoppedError
+	if err == nil && secretMessage == nil {
+		fmt.Println(`>>>> Message retrieved <<<<`)
+		fmt.Printf("%x\n", recoveredMessage)
+	} else if err == nil && len(limits.Blocks) != 0 {
+		fmt.Println(`>>>> Selected blocks retrieved <<<<`)
+		showSelectedBlockMatches(secretMessage, result, blockSize)
+	} else if err == nil && bytes.Equal(secretMessage, recoveredMessage) {
+		fmt.Println(`>>>> Secret message successfully retrieved! <<<<`)
+	} else if errors.As(err, &stoppedError) {
+		fmt.Println(`!!!! Cracking stopped before the secret message was retrieved !!!!`)
+		fmt.Println(err)
+		showPartialMatch(secretMessage, recoveredMessage, stoppedError.RecoveredFrom, limits.Blocks, blockSize)
+	} else if errors.Is(err, attack.ErrOnlyLastBytes) {
+		// Lenient padding is PKCS#7 padding, so the padded secret message is known.
+		fmt.Println(err)
+		showLastByteMatches(padding.Pad(secretMessage, blockSize), recoveredMessage, blockSize)
+	} else if err != nil && secretMessage != nil && len(recoveredMessage) == paddedLength {
+		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
+		fmt.Println(err)
+		showPartialMatch(secretMessage, recoveredMessage, result.RecoveredFrom, limits.Blocks, blockSize)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		// Nothing has been recovered, if cracking failed before it started.
+		if err == nil || len(recoveredMessage) != 0 {
+			showDiff(secretMessage, recoveredMessage)
+		}
+	}
+
+	// 6. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s decryption calls needed %v. This means %d calls per byte.\n",
+		numberformat.FormatInt(count),
+		elapsedTime,
+		int(math.Round(float64(count)/float64(paddedLength))))
+	if result.VerifiedCount != 0 {
+		fmt.Printf("%s known bytes have been verified with two calls each, which saved %s calls.\n",
+			numberformat.FormatInt(result.VerifiedCount),
+			numberformat.FormatInt(result.SavedQueryCount))
+	}
+
+	return result
+}
+
+// fillTemplate fills the unknown bytes of a known plaintext template with random characters.
+// It returns nil, if there is no template.
+func fillTemplate(template *attack.KnownPlaintext) []byte {
+	if template == nil {
+		return nil
+	}
+
+	return template.Fill(func() byte {
+		return secretTextCharacters[randomsource.Intn(len(secretTextCharacters))]
+	})
+}
+
+// showProgress shows the detected block size and padding scheme and the number of queries while cracking.
+func showProgress(event attack.Event) {
+	switch event.Kind {
+	case attack.EventDetected:
+		fmt.Printf("\nDetected block size %d and padding scheme '%s' with %s calls\n\n",
+			event.BlockSize,
+			event.Scheme.Name(),
+			numberformat.FormatInt(event.QueryCount))
+
+	case attack.EventQueryCount:
+		fmt.Printf("Guess count: %9s\n", numberformat.FormatInt(event.QueryCount))
+	}
+}
+
+// showPartialMatch shows how many of the recovered bytes from an index on match the secret message.
+// Recovered padding bytes and bytes of blocks that have not been selected are not counted.
+func showPartialMatch(secretMessage []byte, recoveredMessage []byte, recoveredFrom int, blocks []int, blockSize int) {
+	matchCount := 0
+	checkCount := 0
+	for i := recoveredFrom; i < min(len(secretMessage), len(recoveredMessage)); i++ {
+		if len(blocks) != 0 && !slices.Contains(blocks, i/blockSize+1) {
+			continue
+		}
+
+		checkCount++
+		if secretMessage[i] == recoveredMessage[i] {
+			matchCount++
+		}
+	}
+
+	fmt.Printf("%s of %s recovered bytes of the secret message are correct\n",
+		numberformat.FormatInt(matchCount),
+		numberformat.FormatInt(checkCount))
+}
+
+// showSelectedBlockMatches shows how many bytes of the selected blocks have been recovered correctly
+// and the length of the secret message, if the padding length has been recovered.
+// Recovered padding bytes are not counted.
+func showSelectedBlockMatches(secretMessage []byte, result *attack.Result, blockSize int) {
+	matchCount := 0
+	checkCount := 0
+	for i := 1; i < len(result.State.Intermediates); i++ {
+		if result.State.Intermediates[i] == nil {
+			continue
+		}
+
+		for j := (i - 1) * blockSize; j < min(i*blockSize, len(secretMessage)); j++ {
+			checkCount++
+			if secretMessage[j] == result.ClearMessage[j] {
+				matchCount++
+			}
+		}
+	}
+
+	fmt.Printf("%s of %s recovered bytes of the secret message are correct\n",
+		numberformat.FormatInt(matchCount),
+		numberformat.FormatInt(checkCount))
+
+	if result.PaddingLength != 0 {
+		fmt.Printf("Padding length is %d bytes, so the secret message has %s bytes\n",
+			result.PaddingLength,
+			numberformat.FormatInt(len(result.ClearMessage)-result.PaddingLength))
+	}
+}
+
+// showLastByteMatches shows how many of the last bytes of the blocks have been recovered correctly.
+func showLastByteMatches(paddedMessage []byte, recoveredMessage []byte, blockSize int) {
+	matchCount := 0
+	blockCount := 0
+	for end := blockSize; end <= min(len(paddedMessage), len(recoveredMessage)); end += blockSize {
+		blockCount++
+		if paddedMessage[end-1] == recoveredMessage[end-1] {
+			matchCount++
+		}
+	}
+
+	fmt.Printf("%d of %d last bytes of the blocks recovered correctly\n", matchCount, blockCount)
+}
+
+// showDiff shows the difference between two byte slices.
+func showDiff(a []byte, b []byte) {
+	if len(a) != len(b) {
+		fmt.Printf("Lengths differ: %d != %d\n", len(a), len(b))
+	}
+
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if a[i] != b[i] {
+			fmt.Printf("%d: %02x != %02x\n", i, a[i], b[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Take the answers from package oracle.
+//    2026-10-18: V1.2.0: Check the input for errors and answer only one line without a key.
+//
+
+// This file contains the mode in which the victim is an external command.
+//
+// It reads lines with hex encoded encrypted messages from the standard input and answers every line
+// with "OK", "PADDING_ERROR" or "DATA_ERROR" on the standard output.
+// The exit code is the one of the last answer, so the victim can also be run once for every query.
+// Without a key only one line is answered, as the random key would not fit the messages of the attacker.
+// This is how many small command line tools and scripts behave, and they can be attacked in the same way.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"padora/oracle"
+	"padora/victim"
+)
+
+// ******** Private constants ********
+
+// Exit codes of the decrypt mode.
+const (
+	decryptExitOk           = 0
+	decryptExitPaddingError = 1
+	decryptExitUsageError   = 2
+	decryptExitDataError    = 3
+	decryptExitReadError    = 4
+)
+
+// errMsgDecryptNoKey is the error message for a second line in decrypt mode without a key.
+const errMsgDecryptNoKey = "The decrypt mode needs a key to answer more than one line\n"
+
+// errMsgDecryptRead is the error message for an error while reading the standard input in decrypt mode.
+const errMsgDecryptRead = "Unable to read the standard input: %v\n"
+
+// maxDecryptLineLength is the maximum length of a line with a hex encoded encrypted message.
+const maxDecryptLineLength = 1 << 20
+
+// ******** Public functions ********
+
+// RunDecrypt answers every line with an encrypted message on the standard input and exits
+// with the exit code of the last answer.
+// Without a key it only answers one line.
+func RunDecrypt(hasKey bool) {
+	exitCode := decryptExitDataError
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxDecryptLineLength)
+	lineCount := 0
+	for scanner.Scan() {
+		lineCount++
+		if lineCount > 1 && !hasKey {
+			_, _ = fmt.Fprint(os.Stderr, errMsgDecryptNoKey)
+			os.Exit(decryptExitUsageError)
+		}
+
+		answer := victim.AnswerLine(scanner.Text(), victim.BlockSize())
+		fmt.Println(answer)
+
+		switch answer {
+		case oracle.LineOk:
+			exitCode = decryptExitOk
+
+		case oracle.LinePaddingError:
+			exitCode = decryptExitPaddingError
+
+		default:
+			exitCode = decryptExitDataError
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgDecryptRead, err)
+		os.Exit(decryptExitReadError)
+	}
+
+	os.Exit(exitCode)
+}
@@ -0,0 +1,204 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use fillers that are not in the secret and confirm the prefix alignment.
+//
+
+// This file contains the cracker functions that perform the byte-at-a-time attack on an [EcbVictim].
+//
+// In ECB mode equal clear blocks are encrypted to equal encrypted blocks.
+// The attacker shifts the secret with its data so that only one unknown secret byte is in a block,
+// preceded by known bytes. Then it lets the victim encrypt blocks with the known bytes and all 256
+// possible values of the last byte. The one that gives the same encrypted block is the secret byte.
+//
+// Before that, the attacker detects the block size, that the victim uses ECB mode
+// and the length of the prefix, all only from the encrypted data.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"padora/slicehelper"
+)
+
+// ******** Private types ********
+
+// ecbOracle lets the victim encrypt data and counts the encryptions.
+type ecbOracle struct {
+	victim *EcbVictim
+	count  int
+}
+
+// ******** Public variables ********
+
+// ErrNoEcbMode signals that the victim does not use ECB mode.
+var ErrNoEcbMode = errors.New(`victim does not use ECB mode`)
+
+// ErrByteNotFound signals that no value of a secret byte matched.
+var ErrByteNotFound = errors.New(`secret byte not found`)
+
+// ******** Private constants ********
+
+// ecbFiller is the byte the attacker's data is filled with.
+// It is not one of the characters of a secret text, so that the secret does not continue the filler blocks.
+const ecbFiller = '#'
+
+// ecbCheckFiller is the byte that confirms the alignment found with [ecbFiller].
+// A prefix that ends with one filler byte can not end with the other one, too.
+const ecbCheckFiller = '*'
+
+// ecbAlignFiller is the byte that is used to align the attacker's data, when the prefix length is detected.
+const ecbAlignFiller = 'B'
+
+// ******** Public functions ********
+
+// CrackEcb cracks the secret of an [EcbVictim].
+// It returns the secret, the detected block size and the number of encryption calls needed.
+func CrackEcb(victim *EcbVictim) ([]byte, int, int, error) {
+	oracle := &ecbOracle{victim: victim}
+
+	// 1. Detect the block size and the length of prefix and secret together.
+	blockSize, prefixAndSecretLength := oracle.detectBlockSize()
+
+	// 2. Detect ECB mode.
+	if !oracle.isEcb(blockSize) {
+		return nil, blockSize, oracle.count, ErrNoEcbMode
+	}
+
+	// 3. Detect the length of the prefix.
+	prefixLength := oracle.detectPrefixLength(blockSize)
+
+	// 4. Crack the secret byte by byte.
+	secret, err := oracle.crackSecret(blockSize, prefixLength, prefixAndSecretLength-prefixLength)
+
+	return secret, blockSize, oracle.count, err
+}
+
+// ******** Private functions ********
+
+// encrypt lets the victim encrypt the attacker's data.
+func (o *ecbOracle) encrypt(attackerData []byte) []byte {
+	o.count++
+	return o.victim.Encrypt(attackerData)
+}
+
+// detectBlockSize detects the block size by adding data until the length of the encrypted data increases.
+// It returns the block size and the length of the victim's data.
+func (o *ecbOracle) detectBlockSize() (int, int) {
+	baseLength := len(o.encrypt(nil))
+
+	for dataLength := 1; ; dataLength++ {
+		length := len(o.encrypt(bytes.Repeat([]byte{ecbFiller}, dataLength)))
+		if length > baseLength {
+			// Now the victim's data and the attacker's data fill whole blocks,
+			// and the padding is a whole block.
+			return length - baseLength, baseLength - dataLength
+		}
+	}
+}
+
+// isEcb checks whether the victim uses ECB mode by letting it encrypt three equal blocks.
+// At least two of them are aligned to blocks and have to be encrypted to equal blocks in ECB mode.
+func (o *ecbOracle) isEcb(blockSize int) bool {
+	return findEqualBlocks(o.encrypt(bytes.Repeat([]byte{ecbFiller}, 3*blockSize)), blockSize) >= 0
+}
+
+// detectPrefixLength detects the length of the prefix.
+// It adds alignment bytes in front of two equal blocks, until the two blocks are encrypted to equal blocks.
+// Then the prefix and the alignment bytes fill whole blocks.
+// The alignment is confirmed with blocks of a different filler, as the end of the prefix may look like the filler.
+func (o *ecbOracle) detectPrefixLength(blockSize int) int {
+	for alignLength := 0; alignLength < blockSize; alignLength++ {
+		index := o.findFillerBlocks(blockSize, alignLength, ecbFiller)
+		if index >= 0 && o.findFillerBlocks(blockSize, alignLength, ecbCheckFiller) == index {
+			return index*blockSize - alignLength
+		}
+	}
+
+	return 0
+}
+
+// findFillerBlocks lets the victim encrypt alignment bytes followed by two blocks of a filler.
+// It returns the index of the first of two equal encrypted blocks, or -1, if there are none.
+func (o *ecbOracle) findFillerBlocks(blockSize int, alignLength int, filler byte) int {
+	attackerData := slicehelper.Concat(
+		bytes.Repeat([]byte{ecbAlignFiller}, alignLength),
+		bytes.Repeat([]byte{filler}, blockSize<<1))
+
+	return findEqualBlocks(o.encrypt(attackerData), blockSize)
+}
+
+// crackSecret cracks the secret byte by byte.
+func (o *ecbOracle) crackSecret(blockSize int, prefixLength int, secretLength int) ([]byte, error) {
+	// The alignment bytes fill up the block with the end of the prefix.
+	alignLength := (blockSize - prefixLength%blockSize) % blockSize
+	skipLength := prefixLength + alignLength
+	align := bytes.Repeat([]byte{ecbFiller}, alignLength)
+
+	// known contains the bytes before the current secret byte.
+	// It starts with filler bytes, so that there are always enough known bytes.
+	known := bytes.Repeat([]byte{ecbFiller}, blockSize-1)
+	for i := 0; i < secretLength; i++ {
+		// 1. Shift the secret so that the current byte is the last one of a block.
+		shiftLength := blockSize - 1 - i%blockSize
+		targetStart := skipLength + (i/blockSize)*blockSize
+		encrypted := o.encrypt(slicehelper.Concat(align, known[:shiftLength]))
+		targetBlock := encrypted[targetStart : targetStart+blockSize]
+
+		// 2. Try all values of the byte after the last known bytes.
+		guessBlock := slicehelper.Concat(align, known[len(known)-blockSize+1:], []byte{0})
+		guessPos := len(guessBlock) - 1
+		found := false
+		for guess := 0; guess < 256; guess++ {
+			guessBlock[guessPos] = byte(guess)
+			encrypted = o.encrypt(guessBlock)
+			if bytes.Equal(encrypted[skipLength:skipLength+blockSize], targetBlock) {
+				known = append(known, byte(guess))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return known[blockSize-1:], ErrByteNotFound
+		}
+	}
+
+	return known[blockSize-1:], nil
+}
+
+// findEqualBlocks finds the first block that is equal to the following block.
+// It returns the index of the block, or -1, if there is none.
+func findEqualBlocks(data []byte, blockSize int) int {
+	for start := 0; start+(blockSize<<1) <= len(data); start += blockSize {
+		if bytes.Equal(data[start:start+blockSize], data[start+blockSize:start+(blockSize<<1)]) {
+			return start / blockSize
+		}
+	}
+
+	return -1
+}
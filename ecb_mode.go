@@ -0,0 +1,78 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode that demonstrates the byte-at-a-time attack on ECB mode.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"padora/numberformat"
+	"time"
+)
+
+// ******** Public functions ********
+
+// RunEcb lets a victim encrypt a secret text in ECB mode and cracks it byte by byte.
+func RunEcb(numBlocks int) {
+	// 1. Generate a secret text.
+	secretMessage := makeSecretText(numBlocks, aesBlockSize)
+	fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+	// 2. Set up the victim.
+	//    Note, that the key, the prefix and the secret message are *not* known to the cracker!
+	victim := NewEcbVictim(secretMessage)
+
+	// 3. Crack the secret message.
+	startTime := time.Now()
+	recoveredMessage, blockSize, count, err := CrackEcb(victim)
+	elapsedTime := time.Since(startTime)
+
+	// 4. Check if the message has successfully been cracked.
+	fmt.Println()
+	fmt.Printf("Detected block size is %d bytes\n", blockSize)
+	if err == nil && bytes.Equal(secretMessage, recoveredMessage) {
+		fmt.Println(`>>>> Secret message successfully retrieved! <<<<`)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		showDiff(secretMessage, recoveredMessage)
+	}
+
+	// 5. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s encryption calls needed %v. This means %s calls per byte.\n",
+		numberformat.FormatInt(count),
+		elapsedTime,
+		numberformat.FormatInt(int(math.Round(float64(count)/float64(len(secretMessage))))))
+}
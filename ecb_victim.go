@@ -0,0 +1,77 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains a victim that encrypts data in ECB mode.
+//
+// The victim puts a random prefix in front of the data the attacker supplies, appends a secret,
+// pads the result with PKCS#7 and encrypts it in ECB mode.
+// This is what happens, e.g., if a service encrypts a token that contains user input and a secret.
+
+package main
+
+import (
+	"crypto/aes"
+	"padora/padding"
+	"padora/randomsource"
+	"padora/slicehelper"
+	"padora/victim"
+)
+
+// ******** Public types ********
+
+// EcbVictim encrypts attacker-controlled data between a random prefix and a secret in ECB mode.
+type EcbVictim struct {
+	prefix []byte
+	secret []byte
+}
+
+// ******** Private constants ********
+
+// maxEcbPrefixLength is the maximum length of the random prefix.
+const maxEcbPrefixLength = aes.BlockSize << 1
+
+// ******** Public functions ********
+
+// NewEcbVictim creates a new victim with a random prefix and a secret.
+func NewEcbVictim(secret []byte) *EcbVictim {
+	prefix := make([]byte, randomsource.Intn(maxEcbPrefixLength+1))
+	randomsource.Bytes(prefix)
+
+	return &EcbVictim{
+		prefix: prefix,
+		secret: secret,
+	}
+}
+
+// Encrypt encrypts the concatenation of the prefix, the attacker's data and the secret.
+func (v *EcbVictim) Encrypt(attackerData []byte) []byte {
+	clearMessage := slicehelper.Concat(v.prefix, attackerData, v.secret)
+	return victim.EncryptECB(padding.Pad(clearMessage, aes.BlockSize))
+}
@@ -0,0 +1,166 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the block size of the victim's cipher.
+//
+
+// This file contains the mode that shows what a padding oracle really leaks: the intermediate decryption state.
+//
+// The clear message is derived from the intermediate bytes and the previous blocks.
+// So, the clear message can be derived for any initialization vector without asking the oracle again,
+// and an initialization vector can be forged, so that the first block decrypts to a chosen text (CBC-R).
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"padora/attack"
+	"padora/randomsource"
+	"padora/victim"
+	"slices"
+)
+
+// ******** Private constants ********
+
+// forgedText is the text the first block is forged to.
+const forgedText = `Forged by oracle`
+
+// ******** Public functions ********
+
+// RunIntermediate recovers the intermediate decryption state of a secret text, or loads it from a file,
+// and re-derives the clear message with a chosen initialization vector.
+// If no initialization vector is chosen, a random one is used.
+func RunIntermediate(numBlocks int, intermediatesPath string, chosenIV []byte) {
+	var state *attack.IntermediateState
+	canVerify := false
+	if len(intermediatesPath) != 0 {
+		// 1. - 2. Load the intermediate state.
+		var err error
+		state, err = attack.LoadIntermediateState(intermediatesPath)
+		if err != nil {
+			fmt.Printf("Unable to load intermediate state '%s': %v\n", intermediatesPath, err)
+			return
+		}
+
+		fmt.Printf("\nLoaded intermediate state from '%s'\n", intermediatesPath)
+	} else {
+		// 1. Generate a secret text and encrypt it.
+		//    Note, that the key is *not* known to the main program!
+		blockSize := victim.BlockSize()
+		secretText := makeSecretText(numBlocks, blockSize)
+		encryptedMessage := victim.PadAndEncrypt(secretText, blockSize)
+
+		// 2. Recover the intermediate state with a padding oracle.
+		result, err := attack.CrackAutomatically(context.Background(),
+			victim.NewLocalPaddingOracle(blockSize),
+			encryptedMessage,
+			attack.Options{Observers: []attack.Observer{attack.ObserverFunc(showProgress)}})
+		if err != nil {
+			fmt.Printf("!!!! Unable to recover the intermediate state: %v !!!!\n", err)
+			return
+		}
+
+		state = result.State
+		canVerify = true
+	}
+
+	// 3. Show the intermediate state.
+	showIntermediateState(state)
+
+	// 4. Re-derive the clear message with the original and a chosen initialization vector.
+	if chosenIV == nil {
+		chosenIV = make([]byte, state.BlockSize)
+		randomsource.Bytes(chosenIV)
+	}
+
+	fmt.Println()
+	fmt.Printf("Clear message with original IV %x:\n%q\n", state.IV(), state.ClearMessage())
+	clearMessage, err := state.ClearMessageWithIV(chosenIV)
+	if err != nil {
+		fmt.Printf("!!!! Unable to re-derive the clear message with IV %x: %v !!!!\n", chosenIV, err)
+		return
+	}
+
+	fmt.Printf("Clear message with chosen IV %x:\n%q\n", chosenIV, clearMessage)
+
+	// 5. Forge an initialization vector, so that the first block decrypts to a chosen text.
+	forgedBlock := make([]byte, state.BlockSize)
+	copy(forgedBlock, forgedText)
+	forgedIV, err := state.PreviousBlockFor(1, forgedBlock)
+	if err != nil {
+		fmt.Printf("!!!! Unable to forge an IV: %v !!!!\n", err)
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("Forged IV %x makes the first block decrypt to %q\n", forgedIV, forgedBlock)
+
+	// 6. Let the victim decrypt the message with the forged initialization vector.
+	if canVerify {
+		showForgedDecryption(state, forgedIV, forgedBlock)
+	}
+}
+
+// ******** Private functions ********
+
+// showIntermediateState shows the previous block, the intermediate bytes and the clear bytes of every block.
+func showIntermediateState(state *attack.IntermediateState) {
+	clearMessage := state.ClearMessage()
+
+	fmt.Println()
+	fmt.Println(`Block  Previous block (C)                Intermediate (I)                  Clear block (C XOR I)`)
+	for i := 1; i < len(state.Intermediates); i++ {
+		previousBlock := state.EncryptedMessage[(i-1)*state.BlockSize : i*state.BlockSize]
+		if state.Intermediates[i] == nil {
+			fmt.Printf("%5d  %x  %-32s  %s\n", i, previousBlock, `not recovered`, ``)
+			continue
+		}
+
+		fmt.Printf("%5d  %x  %x  %q\n",
+			i,
+			previousBlock,
+			state.Intermediates[i],
+			clearMessage[(i-1)*state.BlockSize:i*state.BlockSize])
+	}
+}
+
+// showForgedDecryption lets the victim decrypt the encrypted message with a forged initialization vector
+// and checks whether the first block is the forged one.
+func showForgedDecryption(state *attack.IntermediateState, forgedIV []byte, forgedBlock []byte) {
+	forgedMessage := slices.Concat(forgedIV, state.EncryptedMessage[state.BlockSize:])
+	decryptedMessage, err := victim.DecryptAndUnpad(forgedMessage, state.BlockSize)
+	switch {
+	case err != nil:
+		fmt.Printf("!!!! The victim rejected the forged message: %v !!!!\n", err)
+
+	case len(decryptedMessage) >= state.BlockSize && slices.Equal(decryptedMessage[:state.BlockSize], forgedBlock):
+		fmt.Println(`>>>> The victim decrypted the forged first block! <<<<`)
+
+	default:
+		fmt.Println(`!!!! The victim did not decrypt the forged first block !!!!`)
+	}
+}
@@ -0,0 +1,103 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the library packages.
+//    2026-10-18: V1.2.0: Take the random middle blocks from the random source.
+//
+
+// This file contains the cracker functions that recover the key of a [KeyAsIVVictim].
+//
+// The attacker sends the encrypted message C1 ‖ 0 ‖ C1 ‖ C2 ‖ ... ‖ Cn, where Ci are the encrypted blocks.
+// The first block is decrypted to P1 = D(C1) XOR K, as the key K is the initialization vector,
+// and the third block is decrypted to P3 = D(C1) XOR 0. So P1 XOR P3 = K.
+// The second block is decrypted to garbage, which nearly always contains non-ASCII characters,
+// so the victim reports the decrypted message with P1 and P3 in its error.
+// The following blocks are decrypted as usual, so the padding of the last block is still valid.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"padora/padding"
+	"padora/randomsource"
+	"padora/slicehelper"
+)
+
+// ******** Public variables ********
+
+// ErrNoKeyFound signals that the victim never reported a decrypted message.
+var ErrNoKeyFound = errors.New(`victim did not report a decrypted message`)
+
+// ******** Private constants ********
+
+// keyAsIVMaxTries is the maximum number of messages that are sent to the victim.
+const keyAsIVMaxTries = 10
+
+// ******** Public functions ********
+
+// CrackKeyAsIV recovers the key of a [KeyAsIVVictim] from an encrypted message.
+// It returns the key and the number of decryption calls needed.
+func CrackKeyAsIV(victim *KeyAsIVVictim, encryptedMessage []byte, blockSize int) ([]byte, int, error) {
+	firstBlock := encryptedMessage[:blockSize]
+
+	// The middle block is 0, at first. If the victim does not report the decrypted message,
+	// a random middle block is tried.
+	middleBlock := make([]byte, blockSize)
+	for count := 1; count <= keyAsIVMaxTries; count++ {
+		modifiedMessage := slicehelper.Concat(firstBlock, middleBlock, encryptedMessage)
+
+		var textErr *InvalidTextError
+		_, err := victim.Decrypt(modifiedMessage)
+		if errors.As(err, &textErr) {
+			// K = P1 XOR P3 XOR middle block
+			key := make([]byte, blockSize)
+			for i := 0; i < blockSize; i++ {
+				key[i] = textErr.Text[i] ^ textErr.Text[(blockSize<<1)+i] ^ middleBlock[i]
+			}
+
+			return key, count, nil
+		}
+
+		randomsource.Bytes(middleBlock)
+	}
+
+	return nil, keyAsIVMaxTries, ErrNoKeyFound
+}
+
+// DecryptWithKeyAsIV decrypts and unpads an encrypted message with a key that is also the initialization vector.
+func DecryptWithKeyAsIV(key []byte, encryptedMessage []byte, blockSize int) ([]byte, error) {
+	aesCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptedMessage := make([]byte, len(encryptedMessage))
+	cipher.NewCBCDecrypter(aesCipher, key).CryptBlocks(decryptedMessage, encryptedMessage)
+
+	return padding.Unpad(decryptedMessage, blockSize)
+}
@@ -0,0 +1,82 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode that demonstrates the recovery of a key that is also used as the initialization vector.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"padora/numberformat"
+	"time"
+)
+
+// ******** Public functions ********
+
+// RunKeyAsIV encrypts a secret text with a victim that uses the key as the initialization vector,
+// recovers the key and decrypts the secret text with it.
+func RunKeyAsIV(numBlocks int) {
+	// 1. Generate a secret text. The victim only accepts ASCII text.
+	secretMessage := makeSecretText(numBlocks, aesBlockSize)
+	fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+	// 2. Encrypt the secret text.
+	//    Note, that the key is *not* known to the main program!
+	victim := NewKeyAsIVVictim()
+	encryptedMessage := victim.Encrypt(secretMessage)
+
+	// 3. Recover the key with a chosen encrypted message.
+	startTime := time.Now()
+	key, count, err := CrackKeyAsIV(victim, encryptedMessage, aesBlockSize)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Unable to recover key: %v\n", err)
+		return
+	}
+
+	// 4. Decrypt the secret text with the recovered key.
+	recoveredMessage, err := DecryptWithKeyAsIV(key, encryptedMessage, aesBlockSize)
+	elapsedTime := time.Since(startTime)
+
+	// 5. Check if the message has successfully been decrypted.
+	fmt.Println()
+	if err == nil && bytes.Equal(secretMessage, recoveredMessage) {
+		fmt.Println(`>>>> Secret message successfully retrieved! <<<<`)
+		fmt.Printf("Key: %x\n", key)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
+		showDiff(secretMessage, recoveredMessage)
+	}
+
+	// 6. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s decryption calls needed %v.\n",
+		numberformat.FormatInt(count),
+		elapsedTime)
+}
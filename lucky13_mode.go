@@ -0,0 +1,76 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode that demonstrates the "Lucky Thirteen" timing attack.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"padora/numberformat"
+	"time"
+)
+
+// ******** Public functions ********
+
+// RunLucky13 encrypts a secret message in a TLS-like record and cracks it only from timing information.
+func RunLucky13(numBlocks int) {
+	// 1. Generate a secret message.
+	secretMessage := makeSecretMessage(numBlocks, aesBlockSize)
+	fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+	// 2. Put the secret message in an encrypted record.
+	//    Note, that the keys are *not* known to the main program!
+	victim := NewLucky13Victim()
+	encryptedRecord := victim.SealRecord(secretMessage)
+	recordLength := len(encryptedRecord) - aesBlockSize
+	fmt.Printf("Length of encrypted record is %s bytes\n", numberformat.FormatInt(recordLength))
+
+	// 3. Crack the record only with the timing information.
+	startTime := time.Now()
+	recoveredMessage, count := CrackLucky13(victim, encryptedRecord, aesBlockSize)
+	elapsedTime := time.Since(startTime)
+
+	// 4. Check if the message has successfully been cracked.
+	fmt.Println()
+	if bytes.Equal(secretMessage, recoveredMessage) {
+		fmt.Println(`>>>> Secret message successfully retrieved! <<<<`)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
+		showDiff(secretMessage, recoveredMessage)
+	}
+
+	// 5. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s records needed %v. This means %s records per byte.\n",
+		numberformat.FormatInt(count),
+		elapsedTime,
+		numberformat.FormatInt(int(math.Round(float64(count)/float64(recordLength)))))
+}
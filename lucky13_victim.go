@@ -0,0 +1,188 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.4.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Move MAC calculation to its own file.
+//    2026-10-18: V1.2.0: Use the random source that can be seeded.
+//    2026-10-18: V1.3.0: Use the library packages.
+//    2026-10-18: V1.4.0: Take the jitter from the random source.
+//
+
+// This file contains a victim that processes records like TLS does with CBC cipher suites:
+// The MAC is calculated before the encryption ("MAC-then-encrypt").
+//
+// On receipt, the record is decrypted and unpadded and then the MAC is calculated over the data.
+// The victim always returns the same error, regardless of whether the padding or the MAC was wrong.
+// If the padding is wrong, it calculates the MAC as if there was no padding, as RFC 5246 recommends.
+// Nevertheless, the length of the data the MAC is calculated over depends on the padding.
+// So the number of calls of the hash compression function leaks whether the padding was valid.
+// This is the "Lucky Thirteen" attack by Nadhem AlFardan and Kenneth Paterson (2013).
+//
+// The victim simulates the time an attacker would observe on the network.
+// It is made up of a base time, the time needed for each call of the compression function and some jitter.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/subtle"
+	"padora/randomsource"
+	"padora/slicehelper"
+	"time"
+)
+
+// ******** Public types ********
+
+// Lucky13Victim is a receiver of TLS-like records that leaks the padding validity through its timing.
+type Lucky13Victim struct {
+	aesCipher cipher.Block
+	macKey    []byte
+}
+
+// ******** Private constants ********
+
+// sha1BlockSize is the size of the blocks the SHA-1 compression function processes.
+const sha1BlockSize = sha1.BlockSize
+
+// sha1LengthSize is the size of the length that SHA-1 appends to the last block.
+const sha1LengthSize = 8
+
+// lucky13BaseTime is the simulated time needed to receive and process a record without the MAC calculation.
+const lucky13BaseTime = 20 * time.Microsecond
+
+// lucky13CompressionTime is the simulated time one call of the compression function needs.
+const lucky13CompressionTime = 200 * time.Nanosecond
+
+// lucky13Jitter is the standard deviation of the simulated network jitter.
+const lucky13Jitter = 100 * time.Nanosecond
+
+// ******** Public functions ********
+
+// NewLucky13Victim creates a new victim with random keys.
+// The keys are saved nowhere else.
+func NewLucky13Victim() *Lucky13Victim {
+	key := make([]byte, 16)
+	randomsource.Bytes(key)
+	aesCipher, _ := aes.NewCipher(key)
+	slicehelper.Fill(key, 0)
+
+	macKey := make([]byte, RecordMacSize)
+	randomsource.Bytes(macKey)
+
+	return &Lucky13Victim{
+		aesCipher: aesCipher,
+		macKey:    macKey,
+	}
+}
+
+// SealRecord calculates the MAC of data, pads and encrypts it.
+// It returns the concatenation of the initialization vector and the encrypted record.
+func (v *Lucky13Victim) SealRecord(data []byte) []byte {
+	blockSize := v.aesCipher.BlockSize()
+
+	clearRecord := slicehelper.Concat(data, calculateRecordMac(v.macKey, data))
+
+	// TLS padding consists of paddingLength+1 bytes with the value paddingLength.
+	paddingLength := blockSize - 1 - len(clearRecord)%blockSize
+	padding := make([]byte, paddingLength+1)
+	slicehelper.Fill(padding, byte(paddingLength))
+	clearRecord = slicehelper.Concat(clearRecord, padding)
+
+	iv := make([]byte, blockSize)
+	randomsource.Bytes(iv)
+
+	encryptedRecord := make([]byte, len(clearRecord))
+	cipher.NewCBCEncrypter(v.aesCipher, iv).CryptBlocks(encryptedRecord, clearRecord)
+
+	return slicehelper.Concat(iv, encryptedRecord)
+}
+
+// ProcessRecord decrypts a record, unpads it and checks its MAC.
+// It returns the simulated time an attacker observes and nil or [ErrBadRecordMac].
+func (v *Lucky13Victim) ProcessRecord(record []byte) (time.Duration, error) {
+	blockSize := v.aesCipher.BlockSize()
+
+	recordLength := len(record) - blockSize
+	if recordLength < RecordMacSize+1 || recordLength%blockSize != 0 {
+		return lucky13BaseTime, ErrBadRecordMac
+	}
+
+	iv, encryptedRecord := slicehelper.CutHead(record, blockSize)
+	clearRecord := make([]byte, recordLength)
+	cipher.NewCBCDecrypter(v.aesCipher, iv).CryptBlocks(clearRecord, encryptedRecord)
+
+	// 1. Check padding.
+	maxIndex := recordLength - 1
+	paddingLength := int(clearRecord[maxIndex])
+	isPaddingValid := paddingLength+1+RecordMacSize <= recordLength
+	if isPaddingValid {
+		for i := maxIndex - paddingLength; i < maxIndex; i++ {
+			if clearRecord[i] != byte(paddingLength) {
+				isPaddingValid = false
+			}
+		}
+	}
+
+	// 2. If the padding is invalid, calculate the MAC as if there was no padding.
+	if !isPaddingValid {
+		paddingLength = -1
+	}
+
+	dataLength := recordLength - paddingLength - 1 - RecordMacSize
+	data := clearRecord[:dataLength]
+	receivedMac := clearRecord[dataLength : dataLength+RecordMacSize]
+
+	// 3. Check MAC.
+	isMacValid := subtle.ConstantTimeCompare(calculateRecordMac(v.macKey, data), receivedMac) == 1
+
+	elapsedTime := lucky13BaseTime +
+		time.Duration(hmacSha1Compressions(RecordHeaderSize+dataLength))*lucky13CompressionTime +
+		time.Duration(randomsource.NormFloat64()*float64(lucky13Jitter))
+
+	if isPaddingValid && isMacValid {
+		return elapsedTime, nil
+	}
+
+	return elapsedTime, ErrBadRecordMac
+}
+
+// ******** Private functions ********
+
+// hmacSha1Compressions returns the number of calls of the SHA-1 compression function
+// that are needed to calculate the HMAC of a message with the given length.
+func hmacSha1Compressions(messageLength int) int {
+	// The inner hash processes the inner key block and the message,
+	// the outer hash processes the outer key block and the inner hash.
+	return sha1Compressions(sha1BlockSize+messageLength) + sha1Compressions(sha1BlockSize+sha1.Size)
+}
+
+// sha1Compressions returns the number of calls of the SHA-1 compression function
+// that are needed to hash a message with the given length.
+func sha1Compressions(messageLength int) int {
+	// The message is padded with at least one byte and the length.
+	return (messageLength + 1 + sha1LengthSize + sha1BlockSize - 1) / sha1BlockSize
+}
@@ -20,11 +20,34 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 2.5.2
 //
 // Change history:
 //    2024-06-21: V1.0.0: Created.
 //    2024-08-29: V1.1.0: Show progress information.
+//    2026-10-18: V1.2.0: Add timing measurement mode.
+//    2026-10-18: V1.3.0: Add Lucky Thirteen mode.
+//    2026-10-18: V1.4.0: Add POODLE mode.
+//    2026-10-18: V1.5.0: Add Bleichenbacher mode.
+//    2026-10-18: V1.6.0: Add Manger mode.
+//    2026-10-18: V1.7.0: Add bit-flipping mode.
+//    2026-10-18: V1.8.0: Add key as initialization vector mode.
+//    2026-10-18: V1.9.0: Add ECB mode.
+//    2026-10-18: V1.10.0: Add probe mode.
+//    2026-10-18: V1.11.0: Select padding scheme and detect it when cracking.
+//    2026-10-18: V1.12.0: Add noisy victim.
+//    2026-10-18: V1.13.0: Limit and cancel cracking.
+//    2026-10-18: V1.14.0: Resume cracking from a checkpoint.
+//    2026-10-18: V1.15.0: Record and replay oracle queries.
+//    2026-10-18: V1.16.0: Seed, key and initialization vector options.
+//    2026-10-18: V2.0.0: Use the library packages and move cracking to its own file.
+//    2026-10-18: V2.1.0: Add intermediate mode.
+//    2026-10-18: V2.2.0: Add campaign mode.
+//    2026-10-18: V2.3.0: Select the cipher of the victim.
+//    2026-10-18: V2.4.0: Add decrypt mode.
+//    2026-10-18: V2.5.0: Add server mode.
+//    2026-10-18: V2.5.1: Tell the decrypt mode whether a key has been specified.
+//    2026-10-18: V2.5.2: Exit with a usage error, if the victim can not use the key or the initialization vector.
 //
 
 // This is the main program of the padding oracle demonstration.
@@ -32,13 +55,11 @@
 package main
 
 import (
-	"bytes"
-	crand "crypto/rand"
 	"fmt"
-	"math"
-	"math/rand"
-	"padora/numberformat"
-	"time"
+	"os"
+	"padora/padding"
+	"padora/randomsource"
+	"padora/victim"
 )
 
 // ******** Private constants ********
@@ -46,62 +67,128 @@ import (
 // aesBlockSize is the block size of the AES cipher in bytes.
 const aesBlockSize = 16
 
+// toyKeySize is the size of a random key of a toy cipher in bytes.
+const toyKeySize = 16
+
+// errMsgInvalidVictim is the error message for a key or an initialization vector the victim can not use.
+const errMsgInvalidVictim = "Invalid victim setup: %v\n"
+
+// secretTextCharacters are the characters a secret text consists of.
+const secretTextCharacters = `ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_`
+
 // ******** Main function ********
 
 // main is the main program.
 func main() {
-	// 1. Get number of blocks from command line.
-	numBlocks := GetNumBlocks()
-
-	// 2. Generate a secret message with has a length about the number of blocks.
-	secretMessage := makeSecretMessage(numBlocks, aesBlockSize)
-	fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
-
-	// 3. Encrypt the secret message.
-	//    Note, that the key is *not* known to the main program!
-	encryptedMessage := PadAndEncrypt(secretMessage, aesBlockSize)
-
-	// Padded length is encrypted length minus initialization vector length.
-	paddedLength := len(encryptedMessage) - aesBlockSize
-	fmt.Printf("Length of padded encrypted message is %s bytes\n", numberformat.FormatInt(paddedLength))
-
-	// 4. Crack the message with a padding oracle.
-	//    Note that the cracker does *not* know the key!
-	startTime := time.Now()
-	recoveredMessage, count := Crack(encryptedMessage, aesBlockSize)
-	elapsedTime := time.Since(startTime)
-
-	// 5. Check if the message has successfully been cracked.
-	fmt.Println()
-	if bytes.Compare(secretMessage, recoveredMessage) == 0 {
-		fmt.Println(`>>>> Secret message successfully retrieved! <<<<`)
-	} else {
-		fmt.Println(`!!!! Unable to retrieve secret message!!!!`)
-		showDiff(secretMessage, recoveredMessage)
+	// 1. Get options from command line.
+	options := GetOptions()
+
+	if options.HasSeed {
+		randomsource.UseSeed(options.Seed)
 	}
 
-	// 6. Show some statistics.
-	fmt.Println()
-	fmt.Printf("%s decryption calls needed %v. This means %d calls per byte.\n",
-		numberformat.FormatInt(count),
-		elapsedTime,
-		int(math.Round(float64(count)/float64(paddedLength))))
+	if err := useCipher(options); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, errMsgInvalidVictim, err)
+		options.Usage()
+		os.Exit(2)
+	}
+
+	victim.UsePaddingScheme(padding.SchemeByName(options.Padding))
+	victim.UseConstantTimeUnpad(options.ConstantTime)
+
+	switch options.Mode {
+	case ModeTiming:
+		MeasureTiming(aesBlockSize)
+
+	case ModeLucky13:
+		RunLucky13(options.NumBlocks)
+
+	case ModePoodle:
+		RunPoodle(options.NumBlocks)
+
+	case ModeBleichenbacher:
+		RunBleichenbacher()
+
+	case ModeManger:
+		RunManger()
+
+	case ModeBitflip:
+		RunBitflip()
+
+	case ModeKeyAsIV:
+		RunKeyAsIV(options.NumBlocks)
+
+	case ModeEcb:
+		RunEcb(options.NumBlocks)
+
+	case ModeProbe:
+		RunProbe(options.NumBlocks)
+
+	case ModeIntermediate:
+		RunIntermediate(options.NumBlocks, options.IntermediatesPath, options.ChosenIV)
+
+	case ModeCampaign:
+		RunCampaign(options)
+
+	case ModeDecrypt:
+		RunDecrypt(options.Key != nil)
+
+	case ModeServer:
+		RunServer(options.ListenAddress)
+
+	default:
+		RunCrack(options)
+	}
 }
 
 // ******** Private functions ********
 
+// useCipher selects the cipher of the victim with the key and the initialization vector of the options.
+// A toy cipher gets a random key, if no key has been specified.
+func useCipher(options *Options) error {
+	switch options.Cipher {
+	case CipherToy4, CipherToy8:
+		key := options.Key
+		if key == nil {
+			key = make([]byte, toyKeySize)
+			randomsource.Bytes(key)
+		}
+
+		toyCipher, err := victim.NewToyCipher(key, cipherBlockSizes[options.Cipher])
+		if err != nil {
+			return err
+		}
+
+		victim.UseCipher(toyCipher)
+
+	default:
+		if options.Key != nil {
+			if err := victim.UseKey(options.Key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.IV != nil {
+		return victim.UseIV(options.IV)
+	}
+
+	return nil
+}
+
 // makeSecretMessage builds a random secret message.
 func makeSecretMessage(numBlocks int, blockSize int) []byte {
-	result := make([]byte, numBlocks*blockSize-rand.Intn(blockSize))
-	_, _ = crand.Read(result)
+	result := make([]byte, numBlocks*blockSize-randomsource.Intn(blockSize))
+	randomsource.Bytes(result)
 	return result
 }
 
-// showDiff shows the difference between two byte slices.
-func showDiff(a []byte, b []byte) {
-	for i := 0; i < len(a); i++ {
-		if a[i] != b[i] {
-			fmt.Printf("%d: %02x != %02x\n", i, a[i], b[i])
-		}
+// makeSecretText builds a random secret text that consists only of printable characters.
+func makeSecretText(numBlocks int, blockSize int) []byte {
+	result := makeSecretMessage(numBlocks, blockSize)
+	for i, b := range result {
+		result[i] = secretTextCharacters[int(b)%len(secretTextCharacters)]
 	}
+
+	return result
 }
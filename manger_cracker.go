@@ -0,0 +1,148 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the cracker functions that perform Manger's attack (2001) on RSA with OAEP padding.
+//
+// RSA is malleable: If c is the encryption of m, then c * f^e mod n is the encryption of m * f mod n.
+// If the victim tells whether the first byte of m * f mod n is zero, the attacker knows whether
+// m * f mod n < B with B = 2^(8(k-1)) and k the length of the modulus in bytes.
+// With well-chosen values of f, each answer halves the interval m can be in.
+// So about log2(n) questions are needed to find m.
+
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// ******** Private types ********
+
+// mangerOracle asks the victim whether the first byte of a decrypted message is zero and counts the questions.
+type mangerOracle struct {
+	victim    *OaepVictim
+	publicKey *rsa.PublicKey
+	c         *big.Int
+	e         *big.Int
+	k         int
+	count     int
+}
+
+// ******** Public variables ********
+
+// ErrNoOaepMessage signals that the cracked message has no valid OAEP padding.
+var ErrNoOaepMessage = errors.New(`cracked message has no valid OAEP padding`)
+
+// ******** Public functions ********
+
+// CrackManger cracks a message that has been encrypted for an [OaepVictim].
+// It returns the message and the number of decryption calls needed.
+func CrackManger(victim *OaepVictim, encryptedMessage []byte) ([]byte, int, error) {
+	publicKey := victim.PublicKey()
+	n := publicKey.N
+	oracle := &mangerOracle{
+		victim:    victim,
+		publicKey: publicKey,
+		c:         new(big.Int).SetBytes(encryptedMessage),
+		e:         big.NewInt(int64(publicKey.E)),
+		k:         publicKey.Size(),
+	}
+
+	// B = 2^(8(k-1))
+	bigB := new(big.Int).Lsh(bigOne, uint(8*(oracle.k-1)))
+	twoB := new(big.Int).Mul(bigTwo, bigB)
+
+	// Step 1: Find f1 with B <= f1 * m < 2B by doubling f1.
+	f1 := big.NewInt(2)
+	for oracle.isLessThanB(f1) {
+		f1.Mul(f1, bigTwo)
+	}
+
+	// Step 2: Find f2 with n <= f2 * m < n + B by adding f1/2.
+	halfF1 := new(big.Int).Rsh(f1, 1)
+	f2 := new(big.Int).Add(n, bigB)
+	f2.Div(f2, bigB)
+	f2.Mul(f2, halfF1)
+	for !oracle.isLessThanB(f2) {
+		f2.Add(f2, halfF1)
+	}
+
+	// Step 3: Narrow the interval [mMin, mMax] until it contains only one value.
+	mMin := ceilDiv(n, f2)
+	mMax := new(big.Int).Add(n, bigB)
+	mMax.Div(mMax, f2)
+
+	fTmp := new(big.Int)
+	i := new(big.Int)
+	in := new(big.Int)
+	for mMin.Cmp(mMax) < 0 {
+		// fTmp = 2B / (mMax - mMin)
+		fTmp.Sub(mMax, mMin)
+		fTmp.Div(twoB, fTmp)
+
+		// i = fTmp * mMin / n
+		i.Mul(fTmp, mMin)
+		i.Div(i, n)
+
+		// f3 = i * n / mMin, rounded up
+		in.Mul(i, n)
+		f3 := ceilDiv(in, mMin)
+
+		in.Add(in, bigB)
+		if oracle.isLessThanB(f3) {
+			mMax.Div(in, f3)
+		} else {
+			mMin = ceilDiv(in, f3)
+		}
+	}
+
+	message, err := oaepDecode(sha256.New(), mMin.FillBytes(make([]byte, oracle.k)))
+	if err != nil {
+		return nil, oracle.count, ErrNoOaepMessage
+	}
+
+	return message, oracle.count, nil
+}
+
+// ******** Private functions ********
+
+// isLessThanB asks the victim whether m * f is less than B, i.e. its first byte is zero.
+func (o *mangerOracle) isLessThanB(f *big.Int) bool {
+	o.count++
+
+	// c' = c * f^e mod n
+	modifiedC := new(big.Int).Exp(f, o.e, o.publicKey.N)
+	modifiedC.Mul(modifiedC, o.c)
+	modifiedC.Mod(modifiedC, o.publicKey.N)
+
+	_, err := o.victim.Decrypt(modifiedC.FillBytes(make([]byte, o.k)))
+
+	return !errors.Is(err, ErrIntegerTooLarge)
+}
@@ -0,0 +1,160 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.0.1: Tell that the key does not depend on the seed.
+//
+
+// This file contains an RSA victim that uses OAEP encryption padding.
+//
+// OAEP is a secure padding scheme. However, this victim makes a classic implementation error:
+// It returns a different error if the first byte of the decrypted message is not zero,
+// than if the rest of the OAEP decoding fails. This is all that is needed for Manger's attack.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// ******** Public types ********
+
+// OaepVictim is the owner of an RSA key that decrypts messages with OAEP padding.
+type OaepVictim struct {
+	privateKey *rsa.PrivateKey
+}
+
+// ******** Public variables ********
+
+// ErrIntegerTooLarge signals that the decrypted message does not start with a zero byte.
+var ErrIntegerTooLarge = errors.New(`integer too large`)
+
+// ErrOaepDecoding signals that the OAEP padding of the decrypted message is invalid.
+var ErrOaepDecoding = errors.New(`OAEP decoding error`)
+
+// ******** Public functions ********
+
+// NewOaepVictim creates a new victim with a random RSA key.
+// The key and the encryption do not depend on the seed, as crypto/rsa does not promise deterministic results
+// for a deterministic random source.
+func NewOaepVictim() *OaepVictim {
+	privateKey, err := rsa.GenerateKey(rand.Reader, RsaKeySize)
+	if err != nil {
+		panic(err)
+	}
+
+	return &OaepVictim{privateKey: privateKey}
+}
+
+// PublicKey returns the public key of the victim.
+func (v *OaepVictim) PublicKey() *rsa.PublicKey {
+	return &v.privateKey.PublicKey
+}
+
+// Encrypt encrypts a message for the victim with OAEP padding and SHA-256.
+func (v *OaepVictim) Encrypt(message []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, v.PublicKey(), message, nil)
+}
+
+// Decrypt decrypts an encrypted message and removes the OAEP padding.
+// The error tells whether the first byte was not zero ([ErrIntegerTooLarge]),
+// or whether the rest of the padding was invalid ([ErrOaepDecoding]).
+func (v *OaepVictim) Decrypt(encryptedMessage []byte) ([]byte, error) {
+	encodedMessage := rsaDecryptRaw(v.privateKey, encryptedMessage)
+
+	// This is the implementation error: The first byte is checked on its own.
+	if encodedMessage[0] != 0 {
+		return nil, ErrIntegerTooLarge
+	}
+
+	return oaepDecode(sha256.New(), encodedMessage)
+}
+
+// ******** Private functions ********
+
+// oaepDecode removes the OAEP padding with an empty label from an encoded message.
+// It does not need the key, so it can also be used by the attacker.
+func oaepDecode(h hash.Hash, encodedMessage []byte) ([]byte, error) {
+	hashSize := h.Size()
+	if len(encodedMessage) < (hashSize<<1)+2 || encodedMessage[0] != 0 {
+		return nil, ErrOaepDecoding
+	}
+
+	seed := bytes.Clone(encodedMessage[1 : 1+hashSize])
+	db := bytes.Clone(encodedMessage[1+hashSize:])
+
+	xorMgf1(h, seed, db)
+	xorMgf1(h, db, seed)
+
+	// The data block consists of the hash of the label, zero bytes, a one byte and the message.
+	h.Reset()
+	labelHash := h.Sum(nil)
+	if subtle.ConstantTimeCompare(db[:hashSize], labelHash) != 1 {
+		return nil, ErrOaepDecoding
+	}
+
+	for i := hashSize; i < len(db); i++ {
+		switch db[i] {
+		case 0:
+			continue
+
+		case 1:
+			return db[i+1:], nil
+
+		default:
+			return nil, ErrOaepDecoding
+		}
+	}
+
+	return nil, ErrOaepDecoding
+}
+
+// xorMgf1 XORs the MGF1 mask generated from seed into out.
+func xorMgf1(h hash.Hash, out []byte, seed []byte) {
+	counter := make([]byte, 4)
+	var digest []byte
+
+	done := 0
+	for i := uint32(0); done < len(out); i++ {
+		binary.BigEndian.PutUint32(counter, i)
+
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter)
+		digest = h.Sum(digest[:0])
+
+		for j := 0; j < len(digest) && done < len(out); j++ {
+			out[done] ^= digest[j]
+			done++
+		}
+	}
+}
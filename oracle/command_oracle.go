@@ -0,0 +1,236 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Start a persistent command again, if it has ended.
+//
+
+// This file contains an oracle backend that runs an external command for every query.
+//
+// The encrypted message is encoded as text and either written to the standard input of the command,
+// or it replaces the placeholder in the arguments of the command.
+// The response has the exit code as status and the output of the command as body.
+//
+// Starting a process for every query is slow.
+// So the command can also be run persistently: it is started once and answers one line
+// for every line with an encrypted message written to it.
+// If a persistent command ends, the query fails with a transient failure and the next query starts it again.
+
+package oracle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ******** Public types ********
+
+// CommandOptions contains the options for a [CommandOracle].
+type CommandOptions struct {
+	// Encoding is the encoding of the encrypted messages, [EncodingHex] or [EncodingBase64].
+	// If it is empty, [EncodingHex] is used.
+	Encoding string
+	// Persistent specifies that the command is started once and answers a line for every line written to it.
+	// The status of its responses is always 0.
+	Persistent bool
+}
+
+// CommandOracle is an oracle backend that runs an external command for every query.
+// It has to be closed, if it is persistent.
+type CommandOracle struct {
+	command    []string
+	encode     func([]byte) string
+	persistent bool
+	process    *exec.Cmd
+	input      io.WriteCloser
+	output     *bufio.Reader
+}
+
+// ******** Public constants ********
+
+// Encodings of the encrypted messages.
+const (
+	EncodingHex    = `hex`
+	EncodingBase64 = `base64`
+)
+
+// CommandPlaceholder is the argument of a command that is replaced by the encrypted message.
+// If no argument contains it, the encrypted message is written to the standard input of the command.
+const CommandPlaceholder = `{}`
+
+// ******** Public variables ********
+
+// ErrEmptyCommand signals that no command has been specified.
+var ErrEmptyCommand = errors.New(`command is empty`)
+
+// ErrInvalidEncoding signals that an encoding is not supported.
+var ErrInvalidEncoding = errors.New(`invalid encoding`)
+
+// ErrCommandEnded signals that a persistent command has ended.
+var ErrCommandEnded = errors.New(`persistent command has ended`)
+
+// ******** Public functions ********
+
+// NewCommandOracle creates a new [CommandOracle] for a command and its arguments.
+// A persistent command is started immediately.
+func NewCommandOracle(command []string, options CommandOptions) (*CommandOracle, error) {
+	if len(command) == 0 {
+		return nil, ErrEmptyCommand
+	}
+
+	result := &CommandOracle{command: slices.Clone(command), persistent: options.Persistent}
+	switch options.Encoding {
+	case ``, EncodingHex:
+		result.encode = hex.EncodeToString
+
+	case EncodingBase64:
+		result.encode = base64.StdEncoding.EncodeToString
+
+	default:
+		return nil, ErrInvalidEncoding
+	}
+
+	if options.Persistent {
+		if err := result.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Query runs the command with an encrypted message, or sends it to the persistent command.
+// A non-zero exit code is not an error, but the status of the response.
+// If the persistent command has ended, it is started again.
+func (o *CommandOracle) Query(encryptedMessage []byte) (*Response, error) {
+	if o.persistent {
+		if o.process == nil {
+			if err := o.start(); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrTransientFailure, err)
+			}
+		}
+
+		return o.queryPersistent(encryptedMessage)
+	}
+
+	encodedMessage := o.encode(encryptedMessage)
+	args := slices.Clone(o.command[1:])
+	hasPlaceholder := false
+	for i, arg := range args {
+		if strings.Contains(arg, CommandPlaceholder) {
+			args[i] = strings.ReplaceAll(arg, CommandPlaceholder, encodedMessage)
+			hasPlaceholder = true
+		}
+	}
+
+	process := exec.Command(o.command[0], args...)
+	if !hasPlaceholder {
+		process.Stdin = strings.NewReader(encodedMessage + "\n")
+	}
+
+	startTime := time.Now()
+	output, err := process.Output()
+	latency := time.Since(startTime)
+
+	var exitError *exec.ExitError
+	if err != nil && !errors.As(err, &exitError) {
+		return nil, err
+	}
+
+	return &Response{
+		Status:  process.ProcessState.ExitCode(),
+		Body:    bytes.TrimSpace(output),
+		Latency: latency,
+	}, nil
+}
+
+// Close ends a persistent command by closing its standard input and waits for it.
+func (o *CommandOracle) Close() error {
+	if o.process == nil {
+		return nil
+	}
+
+	_ = o.input.Close()
+	err := o.process.Wait()
+	o.process = nil
+
+	return err
+}
+
+// ******** Private functions ********
+
+// start starts the persistent command.
+func (o *CommandOracle) start() error {
+	process := exec.Command(o.command[0], o.command[1:]...)
+	input, err := process.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	output, err := process.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = process.Start(); err != nil {
+		return err
+	}
+
+	o.process = process
+	o.input = input
+	o.output = bufio.NewReader(output)
+
+	return nil
+}
+
+// queryPersistent writes an encrypted message as a line to the persistent command and reads its answer line.
+// If the command has ended, it is closed, so that the next query starts it again.
+func (o *CommandOracle) queryPersistent(encryptedMessage []byte) (*Response, error) {
+	startTime := time.Now()
+	if _, err := io.WriteString(o.input, o.encode(encryptedMessage)+"\n"); err != nil {
+		_ = o.Close()
+		return nil, fmt.Errorf("%w: %w", ErrTransientFailure, ErrCommandEnded)
+	}
+
+	line, err := o.output.ReadBytes('\n')
+	if err != nil {
+		_ = o.Close()
+		return nil, fmt.Errorf("%w: %w", ErrTransientFailure, ErrCommandEnded)
+	}
+
+	return &Response{
+		Body:    bytes.TrimSpace(line),
+		Latency: time.Since(startTime),
+	}, nil
+}
@@ -0,0 +1,87 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package oracle.
+//    2026-10-18: V2.1.0: Add batch padding oracle.
+//
+
+// This file contains the interfaces of the oracles the crackers ask.
+//
+// An [Oracle] is a backend that receives an encrypted message and responds in some way,
+// e.g. with an HTTP status and a body. It does not know what the response means.
+// A [PaddingOracle] interprets the responses and tells whether the padding was valid.
+// A [BatchPaddingOracle] can also check several encrypted messages at once.
+
+// Package oracle implements the interfaces of the oracles the crackers ask
+// and the oracles that fingerprint, stabilize, record and replay other oracles.
+package oracle
+
+import (
+	"errors"
+	"time"
+)
+
+// ******** Public variables ********
+
+// ErrTransientFailure signals a transient failure of an oracle.
+// Asking the oracle again may succeed.
+var ErrTransientFailure = errors.New(`transient oracle failure`)
+
+// ErrQueryTimeout signals that a query needed more time than allowed.
+var ErrQueryTimeout = errors.New(`query timed out`)
+
+// ******** Public types ********
+
+// Response is the response of an oracle backend to an encrypted message.
+type Response struct {
+	// Status is the status of the response, e.g. an HTTP status code or an exit code.
+	Status int
+	// Body is the content of the response.
+	Body []byte
+	// Latency is the time the backend needed to respond.
+	Latency time.Duration
+}
+
+// Oracle is a backend that receives encrypted messages and responds to them.
+type Oracle interface {
+	// Query sends an encrypted message to the backend and returns its response.
+	Query(encryptedMessage []byte) (*Response, error)
+}
+
+// PaddingOracle tells whether an encrypted message has a valid padding.
+type PaddingOracle interface {
+	// HasValidPadding checks whether an encrypted message has a valid padding.
+	HasValidPadding(encryptedMessage []byte) (bool, error)
+}
+
+// BatchPaddingOracle is a [PaddingOracle] that can check several encrypted messages at once,
+// e.g. by sending them without waiting for the answers in between.
+type BatchPaddingOracle interface {
+	PaddingOracle
+	// HasValidPaddings checks for every encrypted message whether it has a valid padding.
+	// If an error occurs, the answers for the messages before it are returned together with the error.
+	HasValidPaddings(encryptedMessages [][]byte) ([]bool, error)
+}
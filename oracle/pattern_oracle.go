@@ -0,0 +1,84 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains a padding oracle that derives its verdict from the status or the body of a response.
+//
+// Many targets tell an invalid padding by a message, e.g. "bad padding", or by a status,
+// e.g. a non-zero exit code. If this is known, the target does not need to be probed.
+
+package oracle
+
+import (
+	"regexp"
+)
+
+// ******** Public types ********
+
+// PatternPaddingOracle is a padding oracle that asks an oracle backend and
+// derives the verdict from the status or the body of the response.
+type PatternPaddingOracle struct {
+	backend        Oracle
+	invalidPattern *regexp.Regexp
+	validPattern   *regexp.Regexp
+}
+
+// ******** Public functions ********
+
+// NewPatternPaddingOracle creates a new [PatternPaddingOracle] for a backend.
+// If the body of a response matches the invalid pattern, the padding is invalid.
+// Otherwise, if there is a valid pattern, the padding is only valid, if the body matches it.
+// If both patterns are nil, the padding is valid, if the status is 0.
+func NewPatternPaddingOracle(backend Oracle, invalidPattern *regexp.Regexp, validPattern *regexp.Regexp) *PatternPaddingOracle {
+	return &PatternPaddingOracle{
+		backend:        backend,
+		invalidPattern: invalidPattern,
+		validPattern:   validPattern,
+	}
+}
+
+// HasValidPadding asks the backend and checks its response with the patterns or the status.
+func (o *PatternPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	response, err := o.backend.Query(encryptedMessage)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case o.invalidPattern != nil && o.invalidPattern.Match(response.Body):
+		return false, nil
+
+	case o.validPattern != nil:
+		return o.validPattern.Match(response.Body), nil
+
+	case o.invalidPattern != nil:
+		return true, nil
+
+	default:
+		return response.Status == 0, nil
+	}
+}
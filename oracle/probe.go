@@ -0,0 +1,486 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package oracle.
+//    2026-10-18: V2.1.0: Flip the previous byte, as the original padding may be one byte long.
+//
+
+// This file contains the functions that probe an unknown target for a padding oracle.
+//
+// The target is sent systematically mutated versions of a valid encrypted message.
+// The responses are clustered into classes of responses the attacker can not distinguish,
+// i.e. that have the same status, length, body and similar latencies.
+// From the classes the mutations fall into, the prober infers whether there is a padding oracle,
+// the block size and which response classes mean "valid padding".
+//
+// The mutations are:
+//   - Prepending bytes: If as many bytes as a block are prepended, the former initialization vector
+//     becomes the first encrypted block and all other blocks are decrypted as before.
+//     So the padding stays valid. Other lengths lead to garbage.
+//   - Flipping the last byte of the second-to-last block: This changes the last decrypted byte, i.e. the padding.
+//     Nearly all flips lead to an invalid padding. Only very few lead to a valid one.
+//     If the original padding is only one byte long, there may be no valid flip at all.
+//   - Flipping the second-to-last byte of the second-to-last block: If the original padding is one byte long,
+//     this byte does not belong to it and all flips keep the padding valid.
+//   - Truncating a byte or a block and swapping the last two blocks: These show how the target responds
+//     to invalid lengths and garbage.
+
+package oracle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"padora/slicehelper"
+	"slices"
+	"time"
+)
+
+// ******** Public types ********
+
+// ResponseClass is a class of responses that the attacker can not distinguish.
+type ResponseClass struct {
+	// Status is the status of the responses.
+	Status int
+	// Length is the length of the bodies.
+	Length int
+	// BodyHash is the hex encoded SHA-256 hash of the bodies.
+	BodyHash string
+	// MinLatency is the minimum latency of the class, if responses with the same content
+	// are distinguished by their latency.
+	MinLatency time.Duration
+	// MaxLatency is the latency up to which a response belongs to the class.
+	MaxLatency time.Duration
+	// MedianLatency is the median of the latencies of the responses.
+	MedianLatency time.Duration
+	// Count is the number of responses in this class.
+	Count int
+	// Mutations are the names of the mutations that produced responses of this class,
+	// with the number of responses.
+	Mutations map[string]int
+	// IsValidPadding is true, if the class means "valid padding".
+	IsValidPadding bool
+
+	latencies []time.Duration
+}
+
+// Fingerprint is the result of probing a target.
+type Fingerprint struct {
+	// HasPaddingOracle is true, if the target is a padding oracle.
+	HasPaddingOracle bool
+	// BlockSize is the detected block size, or 0, if it could not be detected.
+	BlockSize int
+	// Classes are the classes of responses the target sent.
+	Classes []*ResponseClass
+	// QueryCount is the number of messages sent to the target.
+	QueryCount int
+}
+
+// FingerprintOracle is a [PaddingOracle] that interprets the responses of an [Oracle] with a [Fingerprint].
+type FingerprintOracle struct {
+	backend     Oracle
+	fingerprint *Fingerprint
+}
+
+// ******** Public constants ********
+
+// Names of the mutations.
+const (
+	MutationOriginal      = `original`
+	MutationPrepend       = `prepend`
+	MutationFlipLastByte  = `flip last byte`
+	MutationFlipPrevByte  = `flip previous byte`
+	MutationTruncateByte  = `truncate byte`
+	MutationTruncateBlock = `truncate block`
+	MutationSwapBlocks    = `swap blocks`
+)
+
+// ******** Public variables ********
+
+// ErrMessageTooShort signals that the encrypted message is too short to be probed.
+var ErrMessageTooShort = errors.New(`encrypted message is too short`)
+
+// ******** Private types ********
+
+// probeSample is a response to a mutated message.
+type probeSample struct {
+	mutation string
+	response *Response
+	key      responseKey
+	class    *ResponseClass
+}
+
+// responseKey contains the parts of a response that are compared exactly.
+type responseKey struct {
+	status   int
+	length   int
+	bodyHash string
+}
+
+// ******** Private constants ********
+
+// originalRepetitions is the number of times the original message is sent.
+const originalRepetitions = 3
+
+// maxProbeBlockSize is the maximum block size that is probed for.
+const maxProbeBlockSize = 32
+
+// prevByteFlipCount is the number of flips of the second-to-last byte.
+const prevByteFlipCount = 16
+
+// maxValidFlipRatio is the maximum ratio of last byte flips that may lead to a valid padding.
+const maxValidFlipRatio = 1.0 / 16.0
+
+// minLatencyClassRatio is the minimum ratio of responses with the same content
+// that have to be in a class of its own, when they are split by latency.
+const minLatencyClassRatio = 0.2
+
+// latencyGapFactor is the factor by which the gap between two latency classes has to be larger
+// than the median absolute deviation of the latencies.
+const latencyGapFactor = 4
+
+// minLatencyGapRatio is the minimum size of the gap between two latency classes relative to the median latency.
+const minLatencyGapRatio = 1.0
+
+// minLatencySplitSize is the minimum number of responses with the same content that may be split by latency.
+const minLatencySplitSize = 20
+
+// ******** Public functions ********
+
+// Probe probes a target with mutations of a valid encrypted message.
+func Probe(oracle Oracle, encryptedMessage []byte) (*Fingerprint, error) {
+	if len(encryptedMessage) < 2 {
+		return nil, ErrMessageTooShort
+	}
+
+	samples := make([]*probeSample, 0, 512)
+	query := func(mutation string, message []byte) error {
+		response, err := oracle.Query(message)
+		if err != nil {
+			return err
+		}
+
+		samples = append(samples, &probeSample{mutation: mutation, response: response})
+		return nil
+	}
+
+	// 1. Send the original message and the messages with prepended bytes.
+	for i := 0; i < originalRepetitions; i++ {
+		if err := query(MutationOriginal, encryptedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	prependStart := len(samples)
+	for prependLength := 1; prependLength <= maxProbeBlockSize; prependLength++ {
+		if err := query(MutationPrepend, slicehelper.Concat(make([]byte, prependLength), encryptedMessage)); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Fingerprint{}
+	result.Classes = clusterSamples(samples)
+	result.BlockSize = detectBlockSizeByPrepending(samples[prependStart:], len(encryptedMessage))
+
+	// 2. With the block size known, send the other mutations.
+	blockSize := result.BlockSize
+	if blockSize == 0 {
+		result.QueryCount = len(samples)
+		return result, nil
+	}
+
+	flipStart := len(samples)
+	flipPos := len(encryptedMessage) - blockSize - 1
+	for flip := 1; flip < 256; flip++ {
+		flippedMessage := slices.Clone(encryptedMessage)
+		flippedMessage[flipPos] ^= byte(flip)
+		if err := query(MutationFlipLastByte, flippedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	flipEnd := len(samples)
+	for flip := 1; flip <= prevByteFlipCount && flipPos > 0; flip++ {
+		flippedMessage := slices.Clone(encryptedMessage)
+		flippedMessage[flipPos-1] ^= byte(flip)
+		if err := query(MutationFlipPrevByte, flippedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	prevFlipEnd := len(samples)
+	if err := query(MutationTruncateByte, encryptedMessage[:len(encryptedMessage)-1]); err != nil {
+		return nil, err
+	}
+
+	if len(encryptedMessage) >= 3*blockSize {
+		lastStart := len(encryptedMessage) - blockSize
+		if err := query(MutationTruncateBlock, encryptedMessage[:lastStart]); err != nil {
+			return nil, err
+		}
+
+		swappedMessage := slicehelper.Concat(
+			encryptedMessage[:lastStart-blockSize],
+			encryptedMessage[lastStart:],
+			encryptedMessage[lastStart-blockSize:lastStart])
+		if err := query(MutationSwapBlocks, swappedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Cluster all responses and find the classes that mean "valid padding".
+	result.Classes = clusterSamples(samples)
+	result.HasPaddingOracle = markValidPaddingClasses(samples[:originalRepetitions],
+		samples[flipStart:flipEnd],
+		samples[flipEnd:prevFlipEnd])
+	result.QueryCount = len(samples)
+
+	return result, nil
+}
+
+// Classify returns the class of a response, or nil, if the response does not belong to any known class.
+func (f *Fingerprint) Classify(response *Response) *ResponseClass {
+	key := makeResponseKey(response)
+	for _, class := range f.Classes {
+		if class.Status == key.status &&
+			class.Length == key.length &&
+			class.BodyHash == key.bodyHash &&
+			response.Latency >= class.MinLatency &&
+			response.Latency < class.MaxLatency {
+			return class
+		}
+	}
+
+	return nil
+}
+
+// NewFingerprintOracle creates a new [FingerprintOracle].
+func NewFingerprintOracle(backend Oracle, fingerprint *Fingerprint) *FingerprintOracle {
+	return &FingerprintOracle{
+		backend:     backend,
+		fingerprint: fingerprint,
+	}
+}
+
+// HasValidPadding sends an encrypted message to the backend and checks whether
+// the response belongs to a class that means "valid padding".
+func (o *FingerprintOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	response, err := o.backend.Query(encryptedMessage)
+	if err != nil {
+		return false, err
+	}
+
+	class := o.fingerprint.Classify(response)
+
+	return class != nil && class.IsValidPadding, nil
+}
+
+// ******** Private functions ********
+
+// makeResponseKey makes the key of a response.
+func makeResponseKey(response *Response) responseKey {
+	bodyHash := sha256.Sum256(response.Body)
+	return responseKey{
+		status:   response.Status,
+		length:   len(response.Body),
+		bodyHash: hex.EncodeToString(bodyHash[:]),
+	}
+}
+
+// clusterSamples clusters the samples into response classes and sets the class of each sample.
+// Responses with the same status, length and body are split into two classes,
+// if their latencies clearly fall into two groups.
+func clusterSamples(samples []*probeSample) []*ResponseClass {
+	groups := make(map[responseKey][]*probeSample)
+	keys := make([]responseKey, 0)
+	for _, sample := range samples {
+		sample.key = makeResponseKey(sample.response)
+		if _, found := groups[sample.key]; !found {
+			keys = append(keys, sample.key)
+		}
+
+		groups[sample.key] = append(groups[sample.key], sample)
+	}
+
+	result := make([]*ResponseClass, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		threshold := findLatencyThreshold(group)
+		if threshold == 0 {
+			result = append(result, makeResponseClass(key, group, 0, math.MaxInt64))
+			continue
+		}
+
+		fastSamples := make([]*probeSample, 0, len(group))
+		slowSamples := make([]*probeSample, 0, len(group))
+		for _, sample := range group {
+			if sample.response.Latency < threshold {
+				fastSamples = append(fastSamples, sample)
+			} else {
+				slowSamples = append(slowSamples, sample)
+			}
+		}
+
+		result = append(result,
+			makeResponseClass(key, fastSamples, 0, threshold),
+			makeResponseClass(key, slowSamples, threshold, math.MaxInt64))
+	}
+
+	return result
+}
+
+// makeResponseClass makes a response class from samples and sets the class of the samples.
+func makeResponseClass(key responseKey, samples []*probeSample, minLatency time.Duration, maxLatency time.Duration) *ResponseClass {
+	result := &ResponseClass{
+		Status:     key.status,
+		Length:     key.length,
+		BodyHash:   key.bodyHash,
+		MinLatency: minLatency,
+		MaxLatency: maxLatency,
+		Count:      len(samples),
+		Mutations:  make(map[string]int),
+		latencies:  make([]time.Duration, 0, len(samples)),
+	}
+
+	for _, sample := range samples {
+		sample.class = result
+		result.Mutations[sample.mutation]++
+		result.latencies = append(result.latencies, sample.response.Latency)
+	}
+
+	result.MedianLatency = Median(result.latencies)
+
+	return result
+}
+
+// findLatencyThreshold finds the latency that separates the samples into a fast and a slow class.
+// It returns 0, if the latencies do not fall into two groups.
+func findLatencyThreshold(samples []*probeSample) time.Duration {
+	if len(samples) < minLatencySplitSize {
+		return 0
+	}
+
+	minClassSize := int(math.Ceil(float64(len(samples)) * minLatencyClassRatio))
+
+	latencies := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		latencies[i] = sample.response.Latency
+	}
+
+	slices.Sort(latencies)
+	median := Median(latencies)
+
+	deviations := make([]time.Duration, len(latencies))
+	for i, latency := range latencies {
+		deviations[i] = max(latency-median, median-latency)
+	}
+
+	minGap := max(latencyGapFactor*Median(deviations), time.Duration(float64(median)*minLatencyGapRatio))
+
+	// Find the largest gap, that leaves enough samples on both sides.
+	var largestGap time.Duration
+	var threshold time.Duration
+	for i := minClassSize; i <= len(latencies)-minClassSize; i++ {
+		gap := latencies[i] - latencies[i-1]
+		if gap > largestGap {
+			largestGap = gap
+			threshold = latencies[i]
+		}
+	}
+
+	if largestGap <= minGap {
+		return 0
+	}
+
+	return threshold
+}
+
+// detectBlockSizeByPrepending detects the block size from the responses to the messages
+// with 1 to [maxProbeBlockSize] prepended bytes.
+// The block size is the smallest prepended length whose response differs from the response
+// to 1 prepended byte and is the same as the response to twice the length.
+// Only the content of the responses is compared, as the latencies of single responses are too noisy.
+func detectBlockSizeByPrepending(samples []*probeSample, messageLength int) int {
+	misalignedKey := samples[0].key
+	for blockSize := 2; blockSize <= len(samples); blockSize++ {
+		if messageLength%blockSize != 0 {
+			continue
+		}
+
+		key := samples[blockSize-1].key
+		if key == misalignedKey {
+			continue
+		}
+
+		if blockSize<<1 > len(samples) || samples[(blockSize<<1)-1].key == key {
+			return blockSize
+		}
+	}
+
+	return 0
+}
+
+// markValidPaddingClasses marks the classes that mean "valid padding" and checks whether there is a padding oracle.
+// Nearly all flips of the last byte have to fall into one class, which means "invalid padding".
+// The few other flips and the original message mean "valid padding".
+// If no flip of the last byte is valid, the original padding may be one byte long.
+// Then all flips of the previous byte have to keep the padding valid.
+func markValidPaddingClasses(originalSamples []*probeSample, flipSamples []*probeSample, prevFlipSamples []*probeSample) bool {
+	flipCounts := make(map[*ResponseClass]int)
+	var invalidClass *ResponseClass
+	for _, sample := range flipSamples {
+		flipCounts[sample.class]++
+		if invalidClass == nil || flipCounts[sample.class] > flipCounts[invalidClass] {
+			invalidClass = sample.class
+		}
+	}
+
+	validFlipCount := len(flipSamples) - flipCounts[invalidClass]
+	if float64(validFlipCount) > float64(len(flipSamples))*maxValidFlipRatio {
+		return false
+	}
+
+	if validFlipCount == 0 {
+		if len(prevFlipSamples) == 0 {
+			return false
+		}
+
+		for _, sample := range slicehelper.Concat(originalSamples, prevFlipSamples) {
+			if sample.class == invalidClass {
+				return false
+			}
+		}
+	}
+
+	for _, sample := range slicehelper.Concat(flipSamples, prevFlipSamples, originalSamples) {
+		if sample.class != invalidClass {
+			sample.class.IsValidPadding = true
+		}
+	}
+
+	return true
+}
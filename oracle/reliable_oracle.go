@@ -0,0 +1,224 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package oracle.
+//    2026-10-18: V2.1.0: Forward batches to the backend and wait before retries.
+//    2026-10-18: V2.1.1: Count only the answered and the failed queries of a batch.
+//
+
+// This file contains a padding oracle that makes the answers of an unreliable padding oracle reliable.
+//
+// Failed queries are retried.
+// Every answer is the majority of several votes.
+// As a valid padding is rare, a positive answer is much more likely to be a false positive than
+// a negative answer is to be a false negative.
+// So positive answers are confirmed by asking again, until the valid votes outnumber the
+// invalid ones by a margin.
+// A lost positive answer is handled by the cracker, which scans a byte again, if no guess was valid.
+//
+// If every answer is taken as it is, batches are forwarded to a backend that supports them,
+// and the messages that have not been answered because of a failure are sent again.
+
+package oracle
+
+import (
+	"errors"
+	"time"
+)
+
+// ******** Public types ********
+
+// ReliabilityOptions contains the options for a [ReliablePaddingOracle].
+type ReliabilityOptions struct {
+	// MaxRetries is the number of times a failed query is retried.
+	MaxRetries int
+	// RetryDelay is the time to wait before the first retry of a failed query.
+	// It doubles with every further retry, so that e.g. a restarted service has time to come back.
+	// 0 means that failed queries are retried immediately.
+	RetryDelay time.Duration
+	// Votes is the number of votes whose majority is the answer.
+	// It should be odd.
+	Votes int
+	// ConfirmationMargin is the number of votes the valid votes have to outnumber the invalid ones,
+	// before a positive answer is accepted.
+	// 0 means that positive answers are not confirmed.
+	ConfirmationMargin int
+}
+
+// ReliablePaddingOracle is a [BatchPaddingOracle] that retries, votes and confirms the answers of a backend.
+type ReliablePaddingOracle struct {
+	backend    PaddingOracle
+	options    ReliabilityOptions
+	queryCount int
+}
+
+// ******** Private constants ********
+
+// maxConfirmationFactor is the factor of the confirmation margin that limits the number of confirmation votes.
+const maxConfirmationFactor = 4
+
+// ******** Public functions ********
+
+// NewReliablePaddingOracle creates a new [ReliablePaddingOracle].
+func NewReliablePaddingOracle(backend PaddingOracle, options ReliabilityOptions) *ReliablePaddingOracle {
+	options.Votes = max(options.Votes, 1)
+	options.MaxRetries = max(options.MaxRetries, 0)
+	options.ConfirmationMargin = max(options.ConfirmationMargin, 0)
+
+	return &ReliablePaddingOracle{
+		backend: backend,
+		options: options,
+	}
+}
+
+// HasValidPadding asks the backend until the answer is reliable.
+func (o *ReliablePaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	// 1. Majority vote.
+	validVotes := 0
+	for vote := 0; vote < o.options.Votes; vote++ {
+		isValid, err := o.query(encryptedMessage)
+		if err != nil {
+			return false, err
+		}
+
+		if isValid {
+			validVotes++
+		}
+	}
+
+	invalidVotes := o.options.Votes - validVotes
+	if validVotes <= invalidVotes {
+		return false, nil
+	}
+
+	// 2. Confirm a positive answer.
+	maxVotes := o.options.Votes + maxConfirmationFactor*o.options.ConfirmationMargin
+	for validVotes-invalidVotes < o.options.ConfirmationMargin && validVotes+invalidVotes < maxVotes {
+		isValid, err := o.query(encryptedMessage)
+		if err != nil {
+			return false, err
+		}
+
+		if isValid {
+			validVotes++
+		} else {
+			invalidVotes++
+		}
+	}
+
+	return validVotes-invalidVotes >= max(o.options.ConfirmationMargin, 1), nil
+}
+
+// HasValidPaddings checks several encrypted messages.
+// If there is only one vote without confirmation and the backend is a [BatchPaddingOracle],
+// the messages are sent to the backend at once. Otherwise, they are asked one by one.
+// If an error occurs, the answers for the messages before it are returned together with the error.
+func (o *ReliablePaddingOracle) HasValidPaddings(encryptedMessages [][]byte) ([]bool, error) {
+	batchBackend, isBatchBackend := o.backend.(BatchPaddingOracle)
+	if !isBatchBackend || o.options.Votes > 1 || o.options.ConfirmationMargin > 0 {
+		result := make([]bool, 0, len(encryptedMessages))
+		for _, encryptedMessage := range encryptedMessages {
+			isValid, err := o.HasValidPadding(encryptedMessage)
+			if err != nil {
+				return result, err
+			}
+
+			result = append(result, isValid)
+		}
+
+		return result, nil
+	}
+
+	// The first message that has not been answered is retried, the others are just sent again.
+	result := make([]bool, 0, len(encryptedMessages))
+	attempt := 0
+	for len(result) < len(encryptedMessages) {
+		answers, err := batchBackend.HasValidPaddings(encryptedMessages[len(result):])
+		result = append(result, answers...)
+		o.queryCount += len(answers)
+		if err == nil {
+			break
+		}
+
+		// The query that failed has been sent, too.
+		o.queryCount++
+
+		if !errors.Is(err, ErrTransientFailure) {
+			return result, err
+		}
+
+		if len(answers) != 0 {
+			attempt = 0
+		}
+
+		if attempt >= o.options.MaxRetries {
+			return result, err
+		}
+
+		o.waitBeforeRetry(attempt)
+		attempt++
+	}
+
+	return result, nil
+}
+
+// QueryCount returns the number of queries sent to the backend.
+func (o *ReliablePaddingOracle) QueryCount() int {
+	return o.queryCount
+}
+
+// ******** Private functions ********
+
+// query asks the backend once and retries on transient failures.
+func (o *ReliablePaddingOracle) query(encryptedMessage []byte) (bool, error) {
+	var err error
+	for attempt := 0; attempt <= o.options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			o.waitBeforeRetry(attempt - 1)
+		}
+
+		var isValid bool
+		o.queryCount++
+		isValid, err = o.backend.HasValidPadding(encryptedMessage)
+		if err == nil {
+			return isValid, nil
+		}
+
+		if !errors.Is(err, ErrTransientFailure) {
+			return false, err
+		}
+	}
+
+	return false, err
+}
+
+// waitBeforeRetry waits before a retry.
+// retry is the number of retries before this one.
+func (o *ReliablePaddingOracle) waitBeforeRetry(retry int) {
+	if o.options.RetryDelay > 0 {
+		time.Sleep(o.options.RetryDelay << retry)
+	}
+}
@@ -0,0 +1,199 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.0.1: Keep the connection of the writer, when a failed read clears it.
+//    2026-10-18: V1.1.0: Add an answer timeout.
+//
+
+// This file contains a padding oracle that asks a service over TCP with a simple line protocol.
+//
+// Every request is a line with a hex encoded encrypted message.
+// The service answers every request with a line that is one of "OK", "PADDING_ERROR" or "DATA_ERROR".
+//
+// The connection is kept open for all queries.
+// A service that does not answer in time is treated like a dropped connection.
+// Several requests are sent at once without waiting for the answers in between (pipelining),
+// so that a batch of guesses needs only about one round trip.
+
+package oracle
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ******** Public types ********
+
+// TcpOptions contains the options of a [TcpPaddingOracle].
+type TcpOptions struct {
+	// AnswerTimeout is the maximum time to wait for the next answer of the service.
+	// If it is 0, there is no limit.
+	AnswerTimeout time.Duration
+}
+
+// TcpPaddingOracle is a [BatchPaddingOracle] that asks a service over TCP with a line protocol.
+// It has to be closed.
+type TcpPaddingOracle struct {
+	address    string
+	options    TcpOptions
+	connection net.Conn
+	reader     *bufio.Reader
+}
+
+// ******** Public constants ********
+
+// Answers of the line protocol.
+const (
+	LineOk           = `OK`
+	LinePaddingError = `PADDING_ERROR`
+	LineDataError    = `DATA_ERROR`
+)
+
+// ******** Public variables ********
+
+// ErrUnknownAnswer signals that a service answered with a line that is not part of the line protocol.
+var ErrUnknownAnswer = errors.New(`unknown answer`)
+
+// ******** Public functions ********
+
+// NewTcpPaddingOracle creates a new [TcpPaddingOracle] and connects it to a service.
+func NewTcpPaddingOracle(address string, options TcpOptions) (*TcpPaddingOracle, error) {
+	result := &TcpPaddingOracle{address: address, options: options}
+	if err := result.connect(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// HasValidPadding sends an encrypted message to the service and checks its answer.
+func (o *TcpPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	answers, err := o.HasValidPaddings([][]byte{encryptedMessage})
+	if err != nil {
+		return false, err
+	}
+
+	return answers[0], nil
+}
+
+// HasValidPaddings sends all encrypted messages to the service at once and checks the answers.
+// A message with an invalid length does not have a valid padding.
+// If the connection fails or an answer takes longer than the answer timeout,
+// the connection is closed and the error is an [ErrTransientFailure].
+// The next query connects again.
+func (o *TcpPaddingOracle) HasValidPaddings(encryptedMessages [][]byte) ([]bool, error) {
+	if o.connection == nil {
+		if err := o.connect(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTransientFailure, err)
+		}
+	}
+
+	// The requests are written while the answers are read, so that neither side blocks the other one.
+	// The writer gets its own reference to the connection, as a failed read closes and clears it.
+	writeErrors := make(chan error, 1)
+	connection := o.connection
+	go func() {
+		writer := bufio.NewWriter(connection)
+		for _, encryptedMessage := range encryptedMessages {
+			_, _ = writer.WriteString(hex.EncodeToString(encryptedMessage))
+			_ = writer.WriteByte('\n')
+		}
+
+		writeErrors <- writer.Flush()
+	}()
+
+	result := make([]bool, 0, len(encryptedMessages))
+	for range encryptedMessages {
+		// The deadline is also the one of the writer, so it ends, too, when an answer is late.
+		if o.options.AnswerTimeout > 0 {
+			_ = connection.SetDeadline(time.Now().Add(o.options.AnswerTimeout))
+		}
+
+		line, err := o.reader.ReadString('\n')
+		if err != nil {
+			o.disconnect()
+			<-writeErrors
+			return result, fmt.Errorf("%w: %w", ErrTransientFailure, err)
+		}
+
+		switch strings.TrimSpace(line) {
+		case LineOk:
+			result = append(result, true)
+
+		case LinePaddingError, LineDataError:
+			result = append(result, false)
+
+		default:
+			o.disconnect()
+			<-writeErrors
+			return result, ErrUnknownAnswer
+		}
+	}
+
+	if err := <-writeErrors; err != nil {
+		o.disconnect()
+		return result, fmt.Errorf("%w: %w", ErrTransientFailure, err)
+	}
+
+	return result, nil
+}
+
+// Close closes the connection to the service.
+func (o *TcpPaddingOracle) Close() error {
+	if o.connection == nil {
+		return nil
+	}
+
+	err := o.connection.Close()
+	o.connection = nil
+
+	return err
+}
+
+// ******** Private functions ********
+
+// connect connects to the service.
+func (o *TcpPaddingOracle) connect() error {
+	connection, err := net.Dial(`tcp`, o.address)
+	if err != nil {
+		return err
+	}
+
+	o.connection = connection
+	o.reader = bufio.NewReader(connection)
+
+	return nil
+}
+
+// disconnect closes a failed connection.
+func (o *TcpPaddingOracle) disconnect() {
+	_ = o.Close()
+}
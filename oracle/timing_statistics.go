@@ -0,0 +1,140 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add timing classifier.
+//    2026-10-18: V2.0.0: Moved to package oracle.
+//
+
+// This file contains the statistical functions that evaluate timing measurements.
+//
+// A timing oracle does not answer with an explicit error.
+// Instead, the attacker has to decide from the time an answer needs, which class it belongs to.
+// As timings are noisy, this decision can only be made on a statistical basis.
+
+package oracle
+
+import (
+	"slices"
+	"time"
+)
+
+// ******** Public types ********
+
+// TimingClassifier classifies timing measurements into a fast and a slow class.
+type TimingClassifier struct {
+	// FastMedian is the median of the fast reference measurements.
+	FastMedian time.Duration
+	// SlowMedian is the median of the slow reference measurements.
+	SlowMedian time.Duration
+	// Threshold is the time that separates the fast from the slow class.
+	Threshold time.Duration
+}
+
+// ******** Public functions ********
+
+// NewTimingClassifier creates a new timing classifier from reference measurements of a fast and a slow class.
+func NewTimingClassifier(fastSamples []time.Duration, slowSamples []time.Duration) *TimingClassifier {
+	fastMedian := Median(fastSamples)
+	slowMedian := Median(slowSamples)
+
+	return &TimingClassifier{
+		FastMedian: fastMedian,
+		SlowMedian: slowMedian,
+		Threshold:  fastMedian + ((slowMedian - fastMedian) >> 1),
+	}
+}
+
+// IsFast checks whether a series of measurements belongs to the fast class.
+// The more measurements are supplied, the more reliable the decision is.
+func (tc *TimingClassifier) IsFast(samples []time.Duration) bool {
+	return Median(samples) < tc.Threshold
+}
+
+// Accuracy returns the ratio of single measurements that are classified correctly.
+// A value near 0.5 means that the classes can not be distinguished by a single measurement.
+func (tc *TimingClassifier) Accuracy(fastSamples []time.Duration, slowSamples []time.Duration) float64 {
+	total := len(fastSamples) + len(slowSamples)
+	if total == 0 {
+		return 0
+	}
+
+	correct := 0
+	for _, sample := range fastSamples {
+		if sample < tc.Threshold {
+			correct++
+		}
+	}
+
+	for _, sample := range slowSamples {
+		if sample >= tc.Threshold {
+			correct++
+		}
+	}
+
+	return float64(correct) / float64(total)
+}
+
+// Median returns the median of a series of durations.
+// The durations are not modified.
+func Median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	middle := len(sorted) >> 1
+	if len(sorted)&1 != 0 {
+		return sorted[middle]
+	}
+
+	return (sorted[middle-1] + sorted[middle]) >> 1
+}
+
+// SlowerRatio returns the ratio of measurement pairs where the [b] measurement is slower
+// than the [a] measurement. The measurements of a pair must have been taken directly one after the other,
+// so that drifts of the machine's speed do not influence the result.
+//
+// A value near 0.5 means that [a] and [b] can not be distinguished, i.e. there is no timing oracle.
+// The farther the value is away from 0.5, the more reliably [a] and [b] can be distinguished.
+func SlowerRatio(a []time.Duration, b []time.Duration) float64 {
+	slower := 0
+	faster := 0
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if b[i] > a[i] {
+			slower++
+		} else if b[i] < a[i] {
+			faster++
+		}
+	}
+
+	if slower+faster == 0 {
+		return 0.5
+	}
+
+	return float64(slower) / float64(slower+faster)
+}
@@ -0,0 +1,327 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package oracle.
+//    2026-10-18: V2.1.0: Forward batches to the backend and replay errors by their kind.
+//    2026-10-18: V2.1.1: Replay an error without a known kind as a plain error.
+//
+
+// This file contains the padding oracles that record queries to a transcript and replay them.
+//
+// A transcript is a file with one JSON object per line.
+// The first line may contain the encrypted message that is cracked.
+// Every other line contains a query: the encrypted message that was sent, the verdict,
+// the latency and the error, if there was one.
+// Errors that the cracker handles in a special way are recorded with their kind,
+// so that they are replayed as errors of the same kind, even if they wrap other errors.
+//
+// Replaying a transcript answers every query with the recorded verdict.
+// As the cracker is deterministic, it sends the same queries again, so a run can be reproduced
+// without the victim.
+
+package oracle
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// ******** Public types ********
+
+// TranscriptEntry is a recorded query.
+type TranscriptEntry struct {
+	// Message is the hex encoded encrypted message that was sent.
+	Message string `json:"message"`
+	// Valid is the verdict of the oracle.
+	Valid bool `json:"valid"`
+	// LatencyNs is the time in nanoseconds the oracle needed to answer.
+	LatencyNs int64 `json:"latencyNs"`
+	// Error is the error message, if the query failed.
+	Error string `json:"error,omitempty"`
+	// ErrorKind is the kind of the error, if it is one of the errors the cracker handles in a special way.
+	ErrorKind string `json:"errorKind,omitempty"`
+}
+
+// Transcript contains the recorded queries.
+type Transcript struct {
+	// EncryptedMessage is the encrypted message that was cracked, or nil, if it was not recorded.
+	EncryptedMessage []byte
+	// Entries are the recorded queries in the order they were sent.
+	Entries []*TranscriptEntry
+}
+
+// RecordingPaddingOracle is a [PaddingOracle] that records every query of a backend to a transcript file.
+// It is a [BatchPaddingOracle] that forwards batches to the backend, if the backend supports them.
+type RecordingPaddingOracle struct {
+	backend PaddingOracle
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// ReplayPaddingOracle is a [PaddingOracle] that answers from a transcript.
+type ReplayPaddingOracle struct {
+	answers map[string][]*TranscriptEntry
+}
+
+// ******** Public variables ********
+
+// ErrNotInTranscript signals that a query has not been recorded in the transcript.
+var ErrNotInTranscript = errors.New(`query is not in the transcript`)
+
+// ******** Private types ********
+
+// transcriptHeader is the first line of a transcript.
+type transcriptHeader struct {
+	EncryptedMessage string `json:"encryptedMessage"`
+}
+
+// transcriptLine contains all fields a line of a transcript may contain.
+type transcriptLine struct {
+	transcriptHeader
+	TranscriptEntry
+}
+
+// replayedError is a recorded error of a known kind.
+// It has the recorded message and is an error of its kind for [errors.Is].
+type replayedError struct {
+	message string
+	kind    error
+}
+
+// ******** Private variables ********
+
+// replayedErrors are the kinds of errors that are replayed as errors of the same kind,
+// so that they are handled the same way.
+var replayedErrors = map[string]error{
+	`transient`: ErrTransientFailure,
+	`timeout`:   ErrQueryTimeout,
+}
+
+// ******** Public functions ********
+
+// NewRecordingPaddingOracle creates a new [RecordingPaddingOracle] that writes to a new transcript file.
+// If the encrypted message is not nil, it is written as the first line.
+func NewRecordingPaddingOracle(backend PaddingOracle, path string, encryptedMessage []byte) (*RecordingPaddingOracle, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	result := &RecordingPaddingOracle{
+		backend: backend,
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+
+	if encryptedMessage != nil {
+		err = result.encoder.Encode(&transcriptHeader{EncryptedMessage: hex.EncodeToString(encryptedMessage)})
+		if err != nil {
+			_ = result.Close()
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// HasValidPadding asks the backend and records the query.
+func (o *RecordingPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	startTime := time.Now()
+	isValid, err := o.backend.HasValidPadding(encryptedMessage)
+	if writeErr := o.record(encryptedMessage, isValid, time.Since(startTime), err); writeErr != nil {
+		return false, writeErr
+	}
+
+	return isValid, err
+}
+
+// HasValidPaddings asks the backend for several encrypted messages at once and records the queries.
+// If the backend is not a [BatchPaddingOracle], the messages are asked one by one.
+// The latency of a batch is divided evenly among its messages.
+func (o *RecordingPaddingOracle) HasValidPaddings(encryptedMessages [][]byte) ([]bool, error) {
+	batchBackend, isBatchBackend := o.backend.(BatchPaddingOracle)
+	if !isBatchBackend {
+		result := make([]bool, 0, len(encryptedMessages))
+		for _, encryptedMessage := range encryptedMessages {
+			isValid, err := o.HasValidPadding(encryptedMessage)
+			if err != nil {
+				return result, err
+			}
+
+			result = append(result, isValid)
+		}
+
+		return result, nil
+	}
+
+	startTime := time.Now()
+	result, err := batchBackend.HasValidPaddings(encryptedMessages)
+	latency := time.Since(startTime) / time.Duration(max(len(encryptedMessages), 1))
+	for i, isValid := range result {
+		if writeErr := o.record(encryptedMessages[i], isValid, latency, nil); writeErr != nil {
+			return result[:i], writeErr
+		}
+	}
+
+	// The error belongs to the first message that has not been answered.
+	if err != nil && len(result) < len(encryptedMessages) {
+		if writeErr := o.record(encryptedMessages[len(result)], false, latency, err); writeErr != nil {
+			return result, writeErr
+		}
+	}
+
+	return result, err
+}
+
+// Close writes the remaining entries and closes the transcript file.
+func (o *RecordingPaddingOracle) Close() error {
+	err := o.writer.Flush()
+	closeErr := o.file.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// LoadTranscript loads a transcript from a file.
+func LoadTranscript(path string) (*Transcript, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	result := &Transcript{}
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		line := &transcriptLine{}
+		if err = decoder.Decode(line); err != nil {
+			return nil, err
+		}
+
+		if len(line.EncryptedMessage) != 0 {
+			result.EncryptedMessage, err = hex.DecodeString(line.EncryptedMessage)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		entry := line.TranscriptEntry
+		result.Entries = append(result.Entries, &entry)
+	}
+
+	return result, nil
+}
+
+// NewReplayPaddingOracle creates a new [ReplayPaddingOracle] that answers from a transcript.
+func NewReplayPaddingOracle(transcript *Transcript) *ReplayPaddingOracle {
+	answers := make(map[string][]*TranscriptEntry)
+	for _, entry := range transcript.Entries {
+		answers[entry.Message] = append(answers[entry.Message], entry)
+	}
+
+	return &ReplayPaddingOracle{answers: answers}
+}
+
+// HasValidPadding answers with the recorded verdict.
+// If a message has been sent several times, the recorded answers are replayed in order
+// and the last one is repeated.
+func (o *ReplayPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	key := hex.EncodeToString(encryptedMessage)
+	entries := o.answers[key]
+	if len(entries) == 0 {
+		return false, ErrNotInTranscript
+	}
+
+	entry := entries[0]
+	if len(entries) > 1 {
+		o.answers[key] = entries[1:]
+	}
+
+	if len(entry.Error) != 0 {
+		return false, makeReplayedError(entry)
+	}
+
+	return entry.Valid, nil
+}
+
+// ******** Private functions ********
+
+// record writes a query to the transcript.
+func (o *RecordingPaddingOracle) record(encryptedMessage []byte, isValid bool, latency time.Duration, err error) error {
+	entry := &TranscriptEntry{
+		Message:   hex.EncodeToString(encryptedMessage),
+		Valid:     isValid,
+		LatencyNs: latency.Nanoseconds(),
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+		for kind, kindErr := range replayedErrors {
+			if errors.Is(err, kindErr) {
+				entry.ErrorKind = kind
+				break
+			}
+		}
+	}
+
+	return o.encoder.Encode(entry)
+}
+
+// makeReplayedError returns the error for a recorded error.
+// An error without a known kind is replayed as a plain error with the recorded message.
+func makeReplayedError(entry *TranscriptEntry) error {
+	kindErr, isKnownKind := replayedErrors[entry.ErrorKind]
+	if !isKnownKind {
+		return errors.New(entry.Error)
+	}
+
+	if entry.Error == kindErr.Error() {
+		return kindErr
+	}
+
+	return &replayedError{message: entry.Error, kind: kindErr}
+}
+
+// Error returns the recorded message of a replayed error.
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the kind of a replayed error.
+func (e *replayedError) Unwrap() error {
+	return e.kind
+}
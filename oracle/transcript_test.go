@@ -0,0 +1,138 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the tests of recording and replaying transcripts.
+
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// ******** Private types ********
+
+// testBatchOracle answers that a message has a valid padding, if its last byte is 1.
+// It fails with a wrapped transient error for messages whose last byte is 0xff.
+type testBatchOracle struct {
+	batchCount int
+}
+
+// ******** Test functions ********
+
+// TestRecordingForwardsBatches checks that the recorder sends batches to a batch backend
+// and records every message of them.
+func TestRecordingForwardsBatches(t *testing.T) {
+	backend := &testBatchOracle{}
+	transcript := recordTranscript(t, backend, [][]byte{{0, 1}, {0, 2}, {0, 1}})
+	if backend.batchCount != 1 {
+		t.Fatalf(`backend got %d batches, expected 1`, backend.batchCount)
+	}
+
+	if len(transcript.Entries) != 3 {
+		t.Fatalf(`transcript has %d entries, expected 3`, len(transcript.Entries))
+	}
+
+	replay := NewReplayPaddingOracle(transcript)
+	for _, message := range [][]byte{{0, 1}, {0, 2}} {
+		isValid, err := replay.HasValidPadding(message)
+		if err != nil || isValid != (message[1] == 1) {
+			t.Fatalf(`replayed answer for %x is %t, %v`, message, isValid, err)
+		}
+	}
+}
+
+// TestReplayKeepsErrorKind checks that a wrapped transient error is replayed as a transient error
+// with the recorded message.
+func TestReplayKeepsErrorKind(t *testing.T) {
+	transcript := recordTranscript(t, &testBatchOracle{}, [][]byte{{0, 1}, {0, 0xff}})
+	if len(transcript.Entries) != 2 || transcript.Entries[1].ErrorKind != `transient` {
+		t.Fatalf(`error kind has not been recorded`)
+	}
+
+	_, err := NewReplayPaddingOracle(transcript).HasValidPadding([]byte{0, 0xff})
+	if !errors.Is(err, ErrTransientFailure) {
+		t.Fatalf(`replayed error '%v' is not a transient failure`, err)
+	}
+
+	if err.Error() != transcript.Entries[1].Error {
+		t.Fatalf(`replayed error is '%v', expected '%s'`, err, transcript.Entries[1].Error)
+	}
+}
+
+// ******** Private functions ********
+
+// recordTranscript records a batch of messages and loads the transcript.
+func recordTranscript(t *testing.T, backend PaddingOracle, messages [][]byte) *Transcript {
+	path := filepath.Join(t.TempDir(), `transcript.jsonl`)
+	recorder, err := NewRecordingPaddingOracle(backend, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = recorder.HasValidPaddings(messages)
+	if err = recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := LoadTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return transcript
+}
+
+// HasValidPadding answers a single message.
+func (o *testBatchOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	lastByte := encryptedMessage[len(encryptedMessage)-1]
+	if lastByte == 0xff {
+		return false, fmt.Errorf("%w: %w", ErrTransientFailure, io.ErrUnexpectedEOF)
+	}
+
+	return lastByte == 1, nil
+}
+
+// HasValidPaddings answers a batch of messages.
+func (o *testBatchOracle) HasValidPaddings(encryptedMessages [][]byte) ([]bool, error) {
+	o.batchCount++
+	result := make([]bool, 0, len(encryptedMessages))
+	for _, encryptedMessage := range encryptedMessages {
+		isValid, err := o.HasValidPadding(encryptedMessage)
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, isValid)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,253 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package padding.
+//
+
+// This file contains the padding schemes the victim can use and the cracker can detect.
+//
+// The schemes differ in the bytes a padding consists of:
+//   - PKCS#7: Every padding byte contains the padding length, e.g. "03 03 03".
+//   - ISO/IEC 7816-4: The padding starts with 0x80 followed by zeros, e.g. "80 00 00".
+//   - ESP (RFC 4303): The padding bytes count up from 1 and the last byte contains
+//     the number of padding bytes before it, e.g. "01 02 02".
+//   - Lenient: The padding is PKCS#7, but the receiver only checks the last byte.
+
+// Package padding implements the padding schemes of block ciphers.
+package padding
+
+import (
+	"padora/slicehelper"
+)
+
+// ******** Public types ********
+
+// Scheme is a padding scheme.
+type Scheme interface {
+	// Name returns the name of the padding scheme.
+	Name() string
+	// Pad pads an unpadded message.
+	Pad(unpaddedMessage []byte, blockSize int) []byte
+	// Unpad unpads a padded message.
+	Unpad(paddedMessage []byte, blockSize int) ([]byte, error)
+	// Tail returns the bytes of a valid padding with a length of paddingLength bytes.
+	// It returns nil, if the receiver does not check the bytes of the padding.
+	Tail(paddingLength int) []byte
+}
+
+// Pkcs7 is the PKCS#7 padding scheme.
+type Pkcs7 struct{}
+
+// Iso7816 is the ISO/IEC 7816-4 padding scheme.
+type Iso7816 struct{}
+
+// Esp is the ESP padding scheme of RFC 4303 without the "next header" byte.
+type Esp struct{}
+
+// Lenient is the PKCS#7 padding scheme with a receiver that only checks the last byte.
+type Lenient struct{}
+
+// ******** Public constants ********
+
+// Names of the padding schemes.
+const (
+	NamePkcs7   = `pkcs7`
+	NameIso7816 = `iso7816`
+	NameEsp     = `esp`
+	NameLenient = `lenient`
+)
+
+// ******** Private constants ********
+
+// iso7816Marker is the first byte of an ISO/IEC 7816-4 padding.
+const iso7816Marker = 0x80
+
+// ******** Public variables ********
+
+// Schemes contains all padding schemes.
+var Schemes = []Scheme{
+	Pkcs7{},
+	Iso7816{},
+	Esp{},
+	Lenient{},
+}
+
+// ******** Public functions ********
+
+// SchemeByName returns the padding scheme with the given name, or nil, if there is none.
+func SchemeByName(name string) Scheme {
+	for _, scheme := range Schemes {
+		if scheme.Name() == name {
+			return scheme
+		}
+	}
+
+	return nil
+}
+
+// SchemeNames returns the names of all padding schemes.
+func SchemeNames() []string {
+	result := make([]string, len(Schemes))
+	for i, scheme := range Schemes {
+		result[i] = scheme.Name()
+	}
+
+	return result
+}
+
+// -------- PKCS#7 --------
+
+// Name returns the name of the PKCS#7 padding scheme.
+func (Pkcs7) Name() string {
+	return NamePkcs7
+}
+
+// Pad pads an unpadded message with PKCS#7.
+func (Pkcs7) Pad(unpaddedMessage []byte, blockSize int) []byte {
+	return Pad(unpaddedMessage, blockSize)
+}
+
+// Unpad unpads a message padded with PKCS#7.
+func (Pkcs7) Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	return Unpad(paddedMessage, blockSize)
+}
+
+// Tail returns a PKCS#7 padding.
+func (Pkcs7) Tail(paddingLength int) []byte {
+	result := make([]byte, paddingLength)
+	slicehelper.Fill(result, byte(paddingLength))
+	return result
+}
+
+// -------- ISO/IEC 7816-4 --------
+
+// Name returns the name of the ISO/IEC 7816-4 padding scheme.
+func (Iso7816) Name() string {
+	return NameIso7816
+}
+
+// Pad pads an unpadded message with ISO/IEC 7816-4.
+func (p Iso7816) Pad(unpaddedMessage []byte, blockSize int) []byte {
+	return slicehelper.Concat(unpaddedMessage, p.Tail(blockSize-len(unpaddedMessage)%blockSize))
+}
+
+// Unpad unpads a message padded with ISO/IEC 7816-4.
+func (Iso7816) Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	minIndex := max(len(paddedMessage)-blockSize, 0)
+	for i := len(paddedMessage) - 1; i >= minIndex; i-- {
+		switch paddedMessage[i] {
+		case 0:
+			continue
+
+		case iso7816Marker:
+			return paddedMessage[:i], nil
+
+		default:
+			return nil, ErrInvalidPadding
+		}
+	}
+
+	return nil, ErrInvalidPadding
+}
+
+// Tail returns an ISO/IEC 7816-4 padding.
+func (Iso7816) Tail(paddingLength int) []byte {
+	result := make([]byte, paddingLength)
+	result[0] = iso7816Marker
+	return result
+}
+
+// -------- ESP --------
+
+// Name returns the name of the ESP padding scheme.
+func (Esp) Name() string {
+	return NameEsp
+}
+
+// Pad pads an unpadded message with ESP.
+func (p Esp) Pad(unpaddedMessage []byte, blockSize int) []byte {
+	return slicehelper.Concat(unpaddedMessage, p.Tail(blockSize-len(unpaddedMessage)%blockSize))
+}
+
+// Unpad unpads a message padded with ESP.
+func (Esp) Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	maxIndex := len(paddedMessage) - 1
+
+	// The last byte contains the number of padding bytes before it.
+	countBytes := int(paddedMessage[maxIndex])
+	if countBytes >= blockSize || countBytes > maxIndex {
+		return nil, ErrInvalidPadding
+	}
+
+	start := maxIndex - countBytes
+	for i := 0; i < countBytes; i++ {
+		if paddedMessage[start+i] != byte(i+1) {
+			return nil, ErrInvalidPadding
+		}
+	}
+
+	return paddedMessage[:start], nil
+}
+
+// Tail returns an ESP padding.
+func (Esp) Tail(paddingLength int) []byte {
+	result := make([]byte, paddingLength)
+	for i := 0; i < paddingLength-1; i++ {
+		result[i] = byte(i + 1)
+	}
+
+	result[paddingLength-1] = byte(paddingLength - 1)
+
+	return result
+}
+
+// -------- Lenient --------
+
+// Name returns the name of the lenient padding scheme.
+func (Lenient) Name() string {
+	return NameLenient
+}
+
+// Pad pads an unpadded message with PKCS#7.
+func (Lenient) Pad(unpaddedMessage []byte, blockSize int) []byte {
+	return Pad(unpaddedMessage, blockSize)
+}
+
+// Unpad unpads a message padded with PKCS#7, but only checks the last byte.
+func (Lenient) Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	messageLength := len(paddedMessage)
+	paddingLength := int(paddedMessage[messageLength-1])
+	if paddingLength == 0 || paddingLength > blockSize || paddingLength > messageLength {
+		return nil, ErrInvalidPadding
+	}
+
+	return paddedMessage[:messageLength-paddingLength], nil
+}
+
+// Tail returns nil, as the receiver does not check the bytes of the padding.
+func (Lenient) Tail(int) []byte {
+	return nil
+}
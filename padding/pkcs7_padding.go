@@ -20,17 +20,20 @@
 //
 // Author: Frank Schwab
 //
-// Version: 1.0.0
+// Version: 2.0.0
 //
 // Change history:
 //    2024-06-21: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Add constant-time unpadding.
+//    2026-10-18: V2.0.0: Moved to package padding.
 //
 
 // This file contains the PKCS#7 padding and unpadding functions.
 
-package main
+package padding
 
 import (
+	"crypto/subtle"
 	"errors"
 	"padora/slicehelper"
 )
@@ -75,3 +78,40 @@ func Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
 	// 2. Now unpad.
 	return paddedMessage[:maxIndex-intLastByte+1], nil
 }
+
+// ConstantTimeUnpad unpads a padded message in constant time.
+// It does the same work for every padding value and has no data-dependent branches
+// or early exits, so the time it needs does not leak where the padding is wrong.
+// Note that the returned error still tells whether the padding was valid.
+func ConstantTimeUnpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	messageLength := len(paddedMessage)
+	maxIndex := messageLength - 1
+
+	// 1. Check padding.
+
+	// Get last byte.
+	lastByte := paddedMessage[maxIndex]
+	intLastByte := int(lastByte)
+
+	// isValid is 1, if the padding is valid, and 0, if it is not.
+	isValid := subtle.ConstantTimeLessOrEq(1, intLastByte) &
+		subtle.ConstantTimeLessOrEq(intLastByte, blockSize) &
+		subtle.ConstantTimeLessOrEq(intLastByte, messageLength)
+
+	// Always check all bytes that may belong to the padding.
+	// Whether a byte really belongs to the padding only changes the result, not the work.
+	checkLength := min(blockSize, messageLength)
+	for distance := 1; distance < checkLength; distance++ {
+		isPaddingByte := subtle.ConstantTimeLessOrEq(distance+1, intLastByte)
+		isEqual := subtle.ConstantTimeByteEq(paddedMessage[maxIndex-distance], lastByte)
+		isValid &= isEqual | (isPaddingByte ^ 1)
+	}
+
+	// 2. Now unpad.
+	unpaddedLength := subtle.ConstantTimeSelect(isValid, messageLength-intLastByte, 0)
+	if isValid == 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return paddedMessage[:unpaddedLength], nil
+}
@@ -0,0 +1,119 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.1
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the library packages.
+//    2026-10-18: V1.1.1: Tell that the key does not depend on the seed.
+//
+
+// This file contains an RSA victim that uses PKCS#1 v1.5 encryption padding.
+//
+// The victim decrypts messages and tells whether the decrypted message is PKCS#1 v1.5 conforming,
+// i.e. whether it starts with the bytes 00 02. This is all that is needed for Bleichenbacher's attack.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"padora/padding"
+)
+
+// ******** Public types ********
+
+// Pkcs1Victim is the owner of an RSA key that decrypts messages with PKCS#1 v1.5 padding.
+type Pkcs1Victim struct {
+	privateKey *rsa.PrivateKey
+}
+
+// ******** Public constants ********
+
+// RsaKeySize is the size of the RSA keys of the victims in bits.
+const RsaKeySize = 1_024
+
+// ******** Public functions ********
+
+// NewPkcs1Victim creates a new victim with a random RSA key.
+// The key and the encryption do not depend on the seed, as crypto/rsa does not promise deterministic results
+// for a deterministic random source.
+func NewPkcs1Victim() *Pkcs1Victim {
+	privateKey, err := rsa.GenerateKey(rand.Reader, RsaKeySize)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Pkcs1Victim{privateKey: privateKey}
+}
+
+// PublicKey returns the public key of the victim.
+func (v *Pkcs1Victim) PublicKey() *rsa.PublicKey {
+	return &v.privateKey.PublicKey
+}
+
+// Encrypt encrypts a message for the victim with PKCS#1 v1.5 padding.
+func (v *Pkcs1Victim) Encrypt(message []byte) ([]byte, error) {
+	return rsa.EncryptPKCS1v15(rand.Reader, v.PublicKey(), message)
+}
+
+// Decrypt decrypts an encrypted message and checks whether it is PKCS#1 v1.5 conforming.
+// It returns [padding.ErrInvalidPadding], if it is not.
+// Only the first two bytes are checked, as a lot of implementations did.
+func (v *Pkcs1Victim) Decrypt(encryptedMessage []byte) error {
+	encodedMessage := rsaDecryptRaw(v.privateKey, encryptedMessage)
+
+	if encodedMessage[0] != 0 || encodedMessage[1] != 2 {
+		return padding.ErrInvalidPadding
+	}
+
+	return nil
+}
+
+// ******** Private functions ********
+
+// rsaDecryptRaw decrypts an encrypted message without removing the padding.
+// It uses the Chinese remainder theorem to speed up the decryption.
+// The result has the length of the modulus in bytes.
+func rsaDecryptRaw(privateKey *rsa.PrivateKey, encryptedMessage []byte) []byte {
+	c := new(big.Int).SetBytes(encryptedMessage)
+	p := privateKey.Primes[0]
+	q := privateKey.Primes[1]
+	precomputed := &privateKey.Precomputed
+
+	// m1 = c^dp mod p, m2 = c^dq mod q
+	m1 := new(big.Int).Exp(c, precomputed.Dp, p)
+	m2 := new(big.Int).Exp(c, precomputed.Dq, q)
+
+	// h = qInv * (m1 - m2) mod p
+	h := m1.Sub(m1, m2)
+	h.Mul(h, precomputed.Qinv)
+	h.Mod(h, p)
+
+	// m = m2 + h * q
+	m := h.Mul(h, q)
+	m.Add(m, m2)
+
+	return m.FillBytes(make([]byte, privateKey.Size()))
+}
@@ -0,0 +1,128 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the cracker functions that perform a POODLE attack
+// ("Padding Oracle On Downgraded Legacy Encryption") by Bodo Möller, Thai Duong and Krzysztof Kotowicz (2014)
+// on a [PoodleConnection].
+//
+// The attacker chooses the body length so that the padding fills a whole block.
+// Then it replaces this last block with the block that contains the secret byte at its end.
+// The server only accepts this record, if the replaced block is decrypted to a last byte that denotes
+// a full block of padding. Then the attacker knows the value of the secret byte.
+// This happens in 1 of 256 requests, on average. By changing the path length the next secret byte
+// is moved to the end of a block. The body length is changed accordingly, so that the padding still fills
+// a whole block.
+//
+// Unlike the padding oracle attack in cracker.go, this attack does not need a padding error
+// that can be distinguished from a MAC error. It works although the records are authenticated.
+// It needs a client that the attacker can make send chosen requests, instead.
+
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+// ******** Private constants ********
+
+// poodleCookieEnd is the end of the cookie that the cracker looks for.
+const poodleCookieEnd = "\r\n"
+
+// ******** Public functions ********
+
+// CrackPoodle cracks the secret cookie of the client of a [PoodleConnection].
+// It returns the cookie and the number of requests that the client had to send.
+func CrackPoodle(connection *PoodleConnection, blockSize int) ([]byte, int) {
+	// 1. Find the body length where the padding fills a whole block.
+	fullPaddingBodyLength, count := findFullPaddingBodyLength(connection, blockSize)
+
+	// 2. Crack the cookie byte by byte, until the end of the cookie is found.
+	recovered := make([]byte, 0, blockSize)
+	knownLength := len(PoodleRequestPrefix) + len(PoodleCookiePrefix)
+	cookieEnd := []byte(poodleCookieEnd)
+	for !bytes.HasSuffix(recovered, cookieEnd) {
+		value, byteCount := crackPoodleByte(connection, knownLength+len(recovered), fullPaddingBodyLength, blockSize)
+		recovered = append(recovered, value)
+		count += byteCount
+	}
+
+	return recovered[:len(recovered)-len(cookieEnd)], count
+}
+
+// ******** Private functions ********
+
+// findFullPaddingBodyLength finds the body length where the padding fills a whole block.
+// This is the length where the record length increases by one block for the first time.
+// The body length is at least one block, so that there is room to move the cookie with the path.
+func findFullPaddingBodyLength(connection *PoodleConnection, blockSize int) (int, int) {
+	baseLength := len(connection.SendRequest(``, blockSize))
+	count := 1
+
+	bodyLength := blockSize
+	for {
+		bodyLength++
+		count++
+		if len(connection.SendRequest(``, bodyLength)) > baseLength {
+			return bodyLength, count
+		}
+	}
+}
+
+// crackPoodleByte cracks the byte at offset [offset] of the request.
+// It returns the value of the byte and the number of requests that were needed.
+func crackPoodleByte(connection *PoodleConnection, offset int, fullPaddingBodyLength int, blockSize int) (byte, int) {
+	// Choose the path length so that the byte is the last one of a block.
+	// The body is shortened by the same length, so that the padding still fills a whole block.
+	pathLength := (blockSize - 1 - offset%blockSize + blockSize) % blockSize
+	path := strings.Repeat(`x`, pathLength)
+	bodyLength := fullPaddingBodyLength - pathLength
+
+	// The block that contains the byte. Block 0 is the initialization vector.
+	targetStart := ((offset+pathLength)/blockSize)*blockSize + blockSize
+	lastPos := blockSize - 1
+
+	count := 0
+	for {
+		count++
+		record := connection.SendRequest(path, bodyLength)
+
+		// Replace the last block with the block that contains the wanted byte.
+		lastStart := len(record) - blockSize
+		modifiedRecord := slices.Clone(record)
+		copy(modifiedRecord[lastStart:], record[targetStart:targetStart+blockSize])
+
+		if connection.ReceiveRecord(modifiedRecord) == nil {
+			// The target block was decrypted to a last byte of blockSize - 1 in the context of the last block.
+			// So the intermediate value of the target block is known and with it the plain text byte.
+			value := byte(lastPos) ^ record[lastStart-1] ^ record[targetStart-1]
+			return value, count
+		}
+	}
+}
@@ -0,0 +1,74 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode that demonstrates the POODLE attack.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"padora/numberformat"
+	"time"
+)
+
+// ******** Public functions ********
+
+// RunPoodle lets a client send requests with a secret cookie and cracks the cookie with the POODLE attack.
+func RunPoodle(numBlocks int) {
+	// 1. Generate a secret cookie.
+	secretCookie := makeSecretText(numBlocks, aesBlockSize)
+	fmt.Printf("\nLength of secret cookie is %s bytes\n", numberformat.FormatInt(len(secretCookie)))
+
+	// 2. Set up the connection between client and server.
+	//    Note, that the keys and the cookie are *not* known to the cracker!
+	connection := NewPoodleConnection(secretCookie)
+
+	// 3. Crack the cookie.
+	startTime := time.Now()
+	recoveredCookie, count := CrackPoodle(connection, aesBlockSize)
+	elapsedTime := time.Since(startTime)
+
+	// 4. Check if the cookie has successfully been cracked.
+	fmt.Println()
+	if bytes.Equal(secretCookie, recoveredCookie) {
+		fmt.Println(`>>>> Secret cookie successfully retrieved! <<<<`)
+		fmt.Printf("Cookie: %s\n", recoveredCookie)
+	} else {
+		fmt.Println(`!!!! Unable to retrieve secret cookie!!!!`)
+		showDiff(secretCookie, recoveredCookie)
+	}
+
+	// 5. Show some statistics.
+	fmt.Println()
+	fmt.Printf("%s requests needed %v. This means %s requests per byte.\n",
+		numberformat.FormatInt(count),
+		elapsedTime,
+		numberformat.FormatInt(int(math.Round(float64(count)/float64(len(secretCookie))))))
+}
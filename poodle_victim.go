@@ -0,0 +1,143 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains the victim of the POODLE attack: A client that sends requests
+// with a secret cookie to a server over a connection that uses SSLv3-like records.
+//
+// The records are protected by a MAC, that is calculated before the data is padded with the SSLv3 padding
+// and encrypted ("MAC-then-encrypt"). As the MAC does not cover the padding and SSLv3 only checks the
+// last padding byte, a block that consists only of padding can be replaced by any other block.
+//
+// The attacker is able to let the client send requests and chooses the path and the length of the body.
+// This is what malicious JavaScript in a browser can do. The attacker sees the encrypted records
+// on the network and can send modified records to the server.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"padora/randomsource"
+	"padora/slicehelper"
+	"strings"
+)
+
+// ******** Public types ********
+
+// PoodleConnection is a connection between a client and a server with SSLv3-like records.
+// The client sends requests that contain a secret cookie.
+type PoodleConnection struct {
+	aesCipher    cipher.Block
+	macKey       []byte
+	secretCookie []byte
+}
+
+// ******** Public constants ********
+
+// PoodleRequestPrefix is the start of every request up to the path.
+const PoodleRequestPrefix = `POST /`
+
+// PoodleCookiePrefix is the part of every request between the path and the cookie.
+const PoodleCookiePrefix = " HTTP/1.1\r\nCookie: session="
+
+// PoodleCookieSuffix is the part of every request that follows the cookie.
+const PoodleCookieSuffix = "\r\n\r\n"
+
+// ******** Public functions ********
+
+// NewPoodleConnection creates a new connection with random keys and the secret cookie of the client.
+// The keys are saved nowhere else.
+func NewPoodleConnection(secretCookie []byte) *PoodleConnection {
+	key := make([]byte, 16)
+	randomsource.Bytes(key)
+	aesCipher, _ := aes.NewCipher(key)
+	slicehelper.Fill(key, 0)
+
+	macKey := make([]byte, RecordMacSize)
+	randomsource.Bytes(macKey)
+
+	return &PoodleConnection{
+		aesCipher:    aesCipher,
+		macKey:       macKey,
+		secretCookie: secretCookie,
+	}
+}
+
+// SendRequest lets the client send a request with the given path and body length.
+// It returns the encrypted record the attacker sees on the network.
+func (c *PoodleConnection) SendRequest(path string, bodyLength int) []byte {
+	var request strings.Builder
+	request.WriteString(PoodleRequestPrefix)
+	request.WriteString(path)
+	request.WriteString(PoodleCookiePrefix)
+	request.Write(c.secretCookie)
+	request.WriteString(PoodleCookieSuffix)
+	request.WriteString(strings.Repeat(`A`, bodyLength))
+
+	data := []byte(request.String())
+	blockSize := c.aesCipher.BlockSize()
+	clearRecord := SSLv3Pad(slicehelper.Concat(data, calculateRecordMac(c.macKey, data)), blockSize)
+
+	iv := make([]byte, blockSize)
+	randomsource.Bytes(iv)
+
+	encryptedRecord := make([]byte, len(clearRecord))
+	cipher.NewCBCEncrypter(c.aesCipher, iv).CryptBlocks(encryptedRecord, clearRecord)
+
+	return slicehelper.Concat(iv, encryptedRecord)
+}
+
+// ReceiveRecord lets the server receive a record.
+// It returns nil, if the record is valid, or [ErrBadRecordMac], if it is not.
+func (c *PoodleConnection) ReceiveRecord(record []byte) error {
+	blockSize := c.aesCipher.BlockSize()
+
+	recordLength := len(record) - blockSize
+	if recordLength < blockSize || recordLength%blockSize != 0 {
+		return ErrBadRecordMac
+	}
+
+	iv, encryptedRecord := slicehelper.CutHead(record, blockSize)
+	clearRecord := make([]byte, recordLength)
+	cipher.NewCBCDecrypter(c.aesCipher, iv).CryptBlocks(clearRecord, encryptedRecord)
+
+	macedData, err := SSLv3Unpad(clearRecord, blockSize)
+	if err != nil || len(macedData) < RecordMacSize {
+		return ErrBadRecordMac
+	}
+
+	data, receivedMac := slicehelper.CutHead(macedData, len(macedData)-RecordMacSize)
+	if subtle.ConstantTimeCompare(calculateRecordMac(c.macKey, data), receivedMac) != 1 {
+		return ErrBadRecordMac
+	}
+
+	return nil
+}
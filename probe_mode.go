@@ -0,0 +1,143 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Crack with context and limits.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains the mode that probes an unknown target for a padding oracle.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"padora/attack"
+	"padora/numberformat"
+	"padora/oracle"
+	"padora/victim"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ******** Public functions ********
+
+// RunProbe probes a target for a padding oracle and, if one is found, cracks a secret message with it.
+func RunProbe(numBlocks int) {
+	// 1. Generate a secret message.
+	secretMessage := makeSecretMessage(numBlocks, aesBlockSize)
+	fmt.Printf("\nLength of secret message is %s bytes\n", numberformat.FormatInt(len(secretMessage)))
+
+	// 2. Encrypt the secret message.
+	//    Note, that the key is *not* known to the main program!
+	encryptedMessage := victim.PadAndEncrypt(secretMessage, aesBlockSize)
+
+	// 3. Probe the target. The prober only knows that the target responds to encrypted messages.
+	target := victim.NewLocalOracle(aesBlockSize)
+	startTime := time.Now()
+	fingerprint, err := oracle.Probe(target, encryptedMessage)
+	elapsedTime := time.Since(startTime)
+	if err != nil {
+		fmt.Printf("Probing failed: %v\n", err)
+		return
+	}
+
+	// 4. Report the fingerprint.
+	showFingerprint(fingerprint)
+	fmt.Printf("%s probes needed %v.\n", numberformat.FormatInt(fingerprint.QueryCount), elapsedTime)
+
+	if !fingerprint.HasPaddingOracle {
+		fmt.Println(`No padding oracle found.`)
+		return
+	}
+
+	// 5. Crack the message with the response classes found by probing.
+	fmt.Printf("\nPadding oracle found. Block size is %d bytes.\n", fingerprint.BlockSize)
+	crackAndReport(context.Background(),
+		oracle.NewFingerprintOracle(target, fingerprint),
+		secretMessage,
+		encryptedMessage,
+		fingerprint.BlockSize,
+		attack.Options{})
+}
+
+// ******** Private functions ********
+
+// showFingerprint prints the response classes of a fingerprint.
+func showFingerprint(fingerprint *oracle.Fingerprint) {
+	fmt.Println()
+	if fingerprint.BlockSize != 0 {
+		fmt.Printf("Detected block size: %d bytes\n", fingerprint.BlockSize)
+	} else {
+		fmt.Println(`Block size could not be detected`)
+	}
+
+	fmt.Println()
+	fmt.Println(`Status  Length  Body hash  Median latency  Count  Meaning          Mutations`)
+	for _, class := range fingerprint.Classes {
+		fmt.Printf("%6d  %6d  %-9s  %14v  %5d  %-15s  %s\n",
+			class.Status,
+			class.Length,
+			class.BodyHash[:8],
+			class.MedianLatency,
+			class.Count,
+			classMeaning(class),
+			formatMutations(class.Mutations))
+	}
+
+	fmt.Println()
+}
+
+// classMeaning returns the meaning of a response class.
+func classMeaning(class *oracle.ResponseClass) string {
+	if class.IsValidPadding {
+		return `valid padding`
+	}
+
+	if class.Mutations[oracle.MutationFlipLastByte] != 0 {
+		return `invalid padding`
+	}
+
+	return `other`
+}
+
+// formatMutations formats the mutations of a response class sorted by name.
+func formatMutations(mutations map[string]int) string {
+	names := make([]string, 0, len(mutations))
+	for name := range mutations {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, mutations[name])
+	}
+
+	return strings.Join(parts, `, `)
+}
@@ -0,0 +1,91 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the library packages.
+//
+
+// This file contains a victim that stores user profiles as encrypted "key=value" strings.
+//
+// The user is able to choose a part of the profile. The characters ';' and '=' are escaped,
+// so that the user can not inject other keys, like "admin=true".
+// The profile is encrypted with [victim.PadAndEncrypt], but not authenticated.
+
+package main
+
+import (
+	"padora/victim"
+	"strings"
+)
+
+// ******** Public constants ********
+
+// ProfilePrefix is the start of every profile up to the user data.
+const ProfilePrefix = `type=profile;name=`
+
+// ProfileSuffix is the end of every profile after the user data.
+const ProfileSuffix = `;admin=false`
+
+// ******** Private constants ********
+
+// profileSeparator separates the "key=value" pairs of a profile.
+const profileSeparator = `;`
+
+// profileAssignment separates the key from the value.
+const profileAssignment = `=`
+
+// profileAdminPair is the "key=value" pair that makes a profile an administrator's one.
+const profileAdminPair = `admin=true`
+
+// ******** Private variables ********
+
+// profileEscaper escapes the characters that have a special meaning in a profile.
+var profileEscaper = strings.NewReplacer(
+	profileSeparator, `%3B`,
+	profileAssignment, `%3D`)
+
+// ******** Public functions ********
+
+// EncryptProfile builds a profile with user data and encrypts it.
+func EncryptProfile(userData string, blockSize int) []byte {
+	profile := ProfilePrefix + profileEscaper.Replace(userData) + ProfileSuffix
+	return victim.PadAndEncrypt([]byte(profile), blockSize)
+}
+
+// IsAdminProfile decrypts a profile and checks whether it contains "admin=true".
+func IsAdminProfile(encryptedProfile []byte, blockSize int) (bool, error) {
+	profile, err := victim.DecryptAndUnpad(encryptedProfile, blockSize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, pair := range strings.Split(string(profile), profileSeparator) {
+		if pair == profileAdminPair {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,92 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V2.0.0: Moved to package randomsource.
+//    2026-10-18: V2.1.0: Add normally distributed random numbers.
+//
+
+// This file contains the source of the random data for secret messages, keys and initialization vectors.
+//
+// Normally, the random data comes from the cryptographic random number generator, so no two runs are alike.
+// If a seed is set, it comes from a deterministic generator, so that every run with the same seed
+// produces the same messages, keys and initialization vectors.
+// This must never be done in a real application!
+
+// Package randomsource implements the source of the random data, which can be made deterministic by a seed.
+package randomsource
+
+import (
+	crand "crypto/rand"
+	"math/rand"
+)
+
+// ******** Private variables ********
+
+// modSeededRandom is the deterministic random number generator, or nil, if no seed has been set.
+var modSeededRandom *rand.Rand
+
+// ******** Public functions ********
+
+// UseSeed makes all random data deterministic.
+func UseSeed(seed int64) {
+	modSeededRandom = rand.New(rand.NewSource(seed))
+}
+
+// Bytes fills a slice with random bytes.
+func Bytes(data []byte) {
+	if modSeededRandom != nil {
+		_, _ = modSeededRandom.Read(data)
+	} else {
+		_, _ = crand.Read(data)
+	}
+}
+
+// Intn returns a random number between 0 (inclusive) and n (exclusive).
+func Intn(n int) int {
+	if modSeededRandom != nil {
+		return modSeededRandom.Intn(n)
+	}
+
+	return rand.Intn(n)
+}
+
+// Float64 returns a random number between 0 (inclusive) and 1 (exclusive).
+func Float64() float64 {
+	if modSeededRandom != nil {
+		return modSeededRandom.Float64()
+	}
+
+	return rand.Float64()
+}
+
+// NormFloat64 returns a normally distributed random number with a mean of 0 and a standard deviation of 1.
+func NormFloat64() float64 {
+	if modSeededRandom != nil {
+		return modSeededRandom.NormFloat64()
+	}
+
+	return rand.NormFloat64()
+}
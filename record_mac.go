@@ -0,0 +1,78 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the MAC calculation for TLS-like records.
+// It is used by the victims that authenticate the data before encrypting it ("MAC-then-encrypt").
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+)
+
+// ******** Public constants ********
+
+// RecordMacSize is the size of the HMAC-SHA1 MAC in bytes.
+const RecordMacSize = sha1.Size
+
+// RecordHeaderSize is the size of the header that is included in the MAC calculation.
+// It consists of the sequence number, the record type, the protocol version and the length.
+// It is the thirteen in "Lucky Thirteen".
+const RecordHeaderSize = 13
+
+// ErrBadRecordMac signals that a record has been rejected.
+// It is returned for invalid paddings and for invalid MACs.
+var ErrBadRecordMac = errors.New(`bad record mac`)
+
+// ******** Private constants ********
+
+// TLS record type and version that are included in the MAC calculation.
+const (
+	tlsRecordTypeApplicationData = 23
+	tlsVersion12                 = 0x0303
+)
+
+// ******** Private functions ********
+
+// calculateRecordMac calculates the HMAC-SHA1 of the record header and the data.
+func calculateRecordMac(macKey []byte, data []byte) []byte {
+	header := make([]byte, RecordHeaderSize)
+	// The sequence number in the first 8 bytes is always 0.
+	header[8] = tlsRecordTypeApplicationData
+	binary.BigEndian.PutUint16(header[9:], tlsVersion12)
+	binary.BigEndian.PutUint16(header[11:], uint16(len(data)))
+
+	mac := hmac.New(sha1.New, macKey)
+	mac.Write(header)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,66 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the mode in which the victim is a TCP service.
+//
+// It answers every line with a hex encoded encrypted message with "OK", "PADDING_ERROR" or "DATA_ERROR".
+// This shows that the attack is not restricted to HTTP, and a cracker can send many guesses at once
+// over the same connection, so that it needs fewer round trips.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"padora/victim"
+)
+
+// ******** Public functions ********
+
+// RunServer lets the victim answer requests in the line protocol on an address until Ctrl-C is pressed.
+func RunServer(listenAddress string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	listener, err := net.Listen(`tcp`, listenAddress)
+	if err != nil {
+		fmt.Printf("Unable to listen on '%s': %v\n", listenAddress, err)
+		return
+	}
+
+	fmt.Printf("The victim listens on %s. Press Ctrl-C to stop.\n", listener.Addr())
+	if err = victim.ServeLineProtocol(ctx, listener, victim.BlockSize()); err != nil {
+		fmt.Printf("The victim stopped listening: %v\n", err)
+		return
+	}
+
+	fmt.Println(`The victim stopped listening.`)
+}
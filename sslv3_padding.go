@@ -0,0 +1,69 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.2.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V1.2.0: Use the library packages.
+//
+
+// This file contains the SSLv3 padding and unpadding functions.
+//
+// SSLv3 padding only specifies the last byte, which contains the number of the other padding bytes.
+// The content of the other padding bytes is arbitrary, so the receiver can not check it.
+// The padding must not be longer than one block.
+
+package main
+
+import (
+	"padora/padding"
+	"padora/randomsource"
+	"padora/slicehelper"
+)
+
+// ******** Public functions ********
+
+// SSLv3Pad pads an unpadded message with random bytes and the padding length.
+func SSLv3Pad(unpaddedMessage []byte, blockSize int) []byte {
+	paddingLength := blockSize - len(unpaddedMessage)%blockSize
+	padding := make([]byte, paddingLength)
+	randomsource.Bytes(padding)
+	padding[paddingLength-1] = byte(paddingLength - 1)
+	return slicehelper.Concat(unpaddedMessage, padding)
+}
+
+// SSLv3Unpad unpads a padded message.
+// Only the last byte can be checked.
+func SSLv3Unpad(paddedMessage []byte, blockSize int) ([]byte, error) {
+	messageLength := len(paddedMessage)
+
+	// The last byte contains the number of padding bytes before it.
+	paddingLength := int(paddedMessage[messageLength-1]) + 1
+
+	if paddingLength > blockSize || paddingLength > messageLength {
+		return nil, padding.ErrInvalidPadding
+	}
+
+	return paddedMessage[:messageLength-paddingLength], nil
+}
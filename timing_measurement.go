@@ -0,0 +1,177 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the library packages.
+//
+
+// This file contains the measurement mode that checks whether the victim's unpad function
+// leaks timing information.
+//
+// The measurement lets the victim decrypt and unpad messages whose paddings are wrong at different
+// positions, or correct. If the time needed for these messages can be distinguished,
+// there is a timing oracle. Independent of this, the victim still returns an explicit error
+// for an invalid padding, so the explicit-error oracle is always present.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"padora/numberformat"
+	"padora/oracle"
+	"padora/victim"
+	"time"
+)
+
+// ******** Private types ********
+
+// timingCase is a message whose decryption time is measured.
+type timingCase struct {
+	name             string
+	encryptedMessage []byte
+	samples          []time.Duration
+	err              error
+}
+
+// ******** Private constants ********
+
+// timingBatchSize is the number of calls that are measured as one sample.
+// Measuring single calls is too imprecise.
+const timingBatchSize = 100
+
+// timingSampleCount is the number of samples that are taken for each case.
+const timingSampleCount = 10_000
+
+// minSlowerRatioDeviation is the deviation of the slower ratio from 0.5 from which on
+// a timing oracle is assumed to be present.
+const minSlowerRatioDeviation = 0.1
+
+// ******** Public functions ********
+
+// MeasureTiming measures whether the victim's unpad function has a timing oracle and an explicit-error oracle.
+func MeasureTiming(blockSize int) {
+	earlyFailure := &timingCase{
+		name:             `Invalid last byte`,
+		encryptedMessage: victim.Encrypt(makeTimingPlainText(blockSize, blockSize, 0)),
+	}
+	lateFailure := &timingCase{
+		name:             `Invalid first padding byte`,
+		encryptedMessage: victim.Encrypt(makeTimingPlainText(blockSize, blockSize-1, byte(blockSize))),
+	}
+	validPadding := &timingCase{
+		name:             `Valid padding`,
+		encryptedMessage: victim.Encrypt(makeTimingPlainText(blockSize, blockSize, byte(blockSize))),
+	}
+
+	timingCases := []*timingCase{earlyFailure, lateFailure, validPadding}
+
+	fmt.Println()
+	fmt.Printf("Measuring %s samples of %d calls for each case\n", numberformat.FormatInt(timingSampleCount), timingBatchSize)
+
+	// Measure the cases interleaved, so that drifts of the machine's speed affect all of them in the same way.
+	// The order is reversed in every other round, so that the order does not influence the result.
+	for i := 0; i < timingSampleCount; i++ {
+		for j := range timingCases {
+			tc := timingCases[j]
+			if i&1 != 0 {
+				tc = timingCases[len(timingCases)-1-j]
+			}
+
+			tc.samples = append(tc.samples, measureBatch(tc.encryptedMessage, blockSize))
+		}
+	}
+
+	fmt.Println()
+	for _, tc := range timingCases {
+		_, tc.err = victim.DecryptAndUnpad(tc.encryptedMessage, blockSize)
+		fmt.Printf("%-27s: median %v per call, result: %s\n",
+			tc.name,
+			oracle.Median(tc.samples)/timingBatchSize,
+			errorText(tc.err))
+	}
+
+	fmt.Println()
+	hasTimingOracle := reportTimingOracle(earlyFailure, lateFailure)
+	hasTimingOracle = reportTimingOracle(earlyFailure, validPadding) || hasTimingOracle
+
+	fmt.Println()
+	if hasTimingOracle {
+		fmt.Println(`>>>> There is a timing oracle <<<<`)
+	} else {
+		fmt.Println(`>>>> There is no timing oracle <<<<`)
+	}
+
+	if (validPadding.err == nil) != (lateFailure.err == nil) {
+		fmt.Println(`>>>> There is an explicit-error oracle <<<<`)
+	} else {
+		fmt.Println(`>>>> There is no explicit-error oracle <<<<`)
+	}
+}
+
+// ******** Private functions ********
+
+// makeTimingPlainText makes a plain text of two blocks whose last [fillLength] bytes are set to [fillValue]
+// and whose last byte is [lastValue].
+func makeTimingPlainText(blockSize int, fillLength int, lastValue byte) []byte {
+	result := make([]byte, blockSize<<1)
+	maxIndex := len(result) - 1
+
+	for i := maxIndex - fillLength + 1; i < maxIndex; i++ {
+		result[i] = byte(blockSize)
+	}
+
+	result[maxIndex] = lastValue
+
+	return result
+}
+
+// measureBatch measures the time needed for a batch of decryptions.
+func measureBatch(encryptedMessage []byte, blockSize int) time.Duration {
+	startTime := time.Now()
+	for i := 0; i < timingBatchSize; i++ {
+		_, _ = victim.DecryptAndUnpad(encryptedMessage, blockSize)
+	}
+
+	return time.Since(startTime)
+}
+
+// reportTimingOracle reports how often case [b] was slower than case [a]
+// and whether this means that there is a timing oracle.
+func reportTimingOracle(a *timingCase, b *timingCase) bool {
+	slowerRatio := oracle.SlowerRatio(a.samples, b.samples)
+	fmt.Printf("'%s' is slower than '%s' in %.1f%% of the measurements\n", b.name, a.name, slowerRatio*100)
+
+	return math.Abs(slowerRatio-0.5) >= minSlowerRatioDeviation
+}
+
+// errorText returns the text of an error, or 'ok', if there is no error.
+func errorText(err error) string {
+	if err == nil {
+		return `ok`
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,181 @@
+//
+// SPDX-FileCopyrightText: Copyright 2024 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.1.0
+//
+// Change history:
+//    2024-06-21: V1.0.0: Created.
+//    2024-06-23: V1.1.0: Use a module global decryption buffer.
+//    2024-11-06: V1.1.1: Generate key randomly.
+//    2026-10-18: V1.2.0: Add ECB encryption.
+//    2026-10-18: V1.3.0: Key and initialization vector can be set or generated deterministically.
+//    2026-10-18: V2.0.0: Moved to package victim.
+//    2026-10-18: V2.1.0: Block cipher can be selected.
+//
+
+// This file contains the CBC encryption and decryption functions.
+// The cipher is AES, unless another one is selected.
+// It also contains an ECB encryption function to show why ECB must not be used.
+
+// Package victim implements a victim that encrypts secret messages with AES or another block cipher in CBC mode,
+// and decrypts and unpads the messages it receives, and the oracles that ask it.
+package victim
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"padora/randomsource"
+	"padora/slicehelper"
+)
+
+// ******** Public variables ********
+
+// ErrInvalidIVLength signals that an initialization vector does not have the length of a block.
+var ErrInvalidIVLength = errors.New(`initialization vector must have the length of a block`)
+
+// ******** Private variables ********
+
+// modCipher is the block cipher.
+// It is instanced only once and reused for every call.
+var modCipher cipher.Block
+
+// modFixedIV is the initialization vector that is used for every encryption, or nil, if it is random.
+var modFixedIV []byte
+
+// ******** Public functions ********
+
+// UseKey selects AES with a key instead of a random one.
+func UseKey(key []byte) error {
+	aesCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	modCipher = aesCipher
+
+	return nil
+}
+
+// UseCipher sets the block cipher instead of AES with a random key.
+// A fixed initialization vector must have the block size of the cipher.
+func UseCipher(block cipher.Block) {
+	modCipher = block
+}
+
+// BlockSize returns the block size of the cipher.
+func BlockSize() int {
+	if modCipher == nil {
+		return aes.BlockSize
+	}
+
+	return modCipher.BlockSize()
+}
+
+// UseIV sets the initialization vector that is used for every encryption instead of a random one.
+// Using the same initialization vector for every encryption is insecure and only done for reproducible demonstrations.
+func UseIV(iv []byte) error {
+	if len(iv) != BlockSize() {
+		return ErrInvalidIVLength
+	}
+
+	modFixedIV = iv
+
+	return nil
+}
+
+// Encrypt encrypts a clear message and returns a concatenation
+// of the initialization vector and the encrypted data.
+func Encrypt(clearMessage []byte) []byte {
+	blockCipher := getCipher()
+
+	iv := make([]byte, blockCipher.BlockSize())
+	if modFixedIV != nil {
+		copy(iv, modFixedIV)
+	} else {
+		randomsource.Bytes(iv)
+	}
+
+	cbcCipher := cipher.NewCBCEncrypter(blockCipher, iv)
+
+	encryptedBytes := make([]byte, len(clearMessage))
+	cbcCipher.CryptBlocks(encryptedBytes, clearMessage)
+
+	return slicehelper.Concat(iv, encryptedBytes)
+}
+
+// Decrypt decrypts a concatenation of an initialization vector and an encrypted message.
+func Decrypt(compoundEncryptedMessage []byte) []byte {
+	blockCipher := getCipher()
+
+	iv, encryptedMessage := slicehelper.CutHead(compoundEncryptedMessage, blockCipher.BlockSize())
+
+	cbcCipher := cipher.NewCBCDecrypter(blockCipher, iv)
+
+	decryptedBytes := getDecryptionBuffer(len(encryptedMessage))
+	cbcCipher.CryptBlocks(decryptedBytes, encryptedMessage)
+
+	return decryptedBytes
+}
+
+// EncryptECB encrypts a clear message in ECB mode, i.e. every block is encrypted on its own.
+// The length of the clear message has to be a multiple of the block size.
+func EncryptECB(clearMessage []byte) []byte {
+	blockCipher := getCipher()
+	blockSize := blockCipher.BlockSize()
+
+	encryptedBytes := make([]byte, len(clearMessage))
+	for start := 0; start < len(clearMessage); start += blockSize {
+		blockCipher.Encrypt(encryptedBytes[start:], clearMessage[start:start+blockSize])
+	}
+
+	return encryptedBytes
+}
+
+// ******** Private functions ********
+
+// getCipher returns the block cipher and creates an AES cipher, if it does not exist, yet.
+func getCipher() cipher.Block {
+	if modCipher == nil {
+		// The key is randomly generated.
+		// It is saved nowhere.
+		key := make([]byte, 16)
+		randomsource.Bytes(key)
+		modCipher, _ = aes.NewCipher(key)
+		slicehelper.Fill(key, 0)
+	}
+
+	return modCipher
+}
+
+// decryptionBuffer is the buffer used for decryption.
+var decryptionBuffer []byte
+
+// getDecryptionBuffer returns the decryption buffer with a guaranteed minimum capacity.
+// This reduces the number of allocations needed for decryption.
+func getDecryptionBuffer(size int) []byte {
+	if cap(decryptionBuffer) < size {
+		decryptionBuffer = make([]byte, size)
+	}
+
+	return decryptionBuffer[:size]
+}
@@ -0,0 +1,67 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.1.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Take the answers from package oracle.
+//
+
+// This file contains the answers of the victim in a simple line protocol.
+//
+// Every request is a line with a hex encoded encrypted message.
+// The victim decrypts and unpads it and answers with a line that is one of
+// "OK", "PADDING_ERROR" or "DATA_ERROR".
+// "DATA_ERROR" means that the line is not hex encoded or the message does not have a valid length.
+
+package victim
+
+import (
+	"encoding/hex"
+	"errors"
+	"padora/oracle"
+	"strings"
+)
+
+// ******** Public functions ********
+
+// AnswerLine lets the victim decrypt and unpad the hex encoded encrypted message of a request line
+// and returns the answer line without a line end.
+func AnswerLine(line string, blockSize int) string {
+	encryptedMessage, err := hex.DecodeString(strings.TrimSpace(line))
+	if err != nil {
+		return oracle.LineDataError
+	}
+
+	_, err = DecryptAndUnpad(encryptedMessage, blockSize)
+	switch {
+	case err == nil:
+		return oracle.LineOk
+
+	case errors.Is(err, ErrInvalidMessageLength):
+		return oracle.LineDataError
+
+	default:
+		return oracle.LinePaddingError
+	}
+}
@@ -0,0 +1,111 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains the oracle backends that ask the local victim.
+
+package victim
+
+import (
+	"padora/oracle"
+	"time"
+)
+
+// ******** Public types ********
+
+// LocalOracle is an [oracle.Oracle] that lets the local victim decrypt and unpad the messages.
+// It responds like a web service that does not care to hide the reason of an error.
+type LocalOracle struct {
+	blockSize int
+}
+
+// LocalPaddingOracle is a [oracle.PaddingOracle] that directly asks the local victim.
+type LocalPaddingOracle struct {
+	blockSize int
+}
+
+// ******** Public constants ********
+
+// Statuses and bodies of the responses of a [LocalOracle].
+const (
+	LocalStatusOk             = 200
+	LocalStatusInvalidLength  = 400
+	LocalStatusInvalidPadding = 500
+
+	localBodyOk             = `OK`
+	localBodyInvalidLength  = `invalid message length`
+	localBodyInvalidPadding = `invalid padding`
+)
+
+// ******** Public functions ********
+
+// NewLocalOracle creates a new [LocalOracle] for the block size of the victim.
+func NewLocalOracle(blockSize int) *LocalOracle {
+	return &LocalOracle{blockSize: blockSize}
+}
+
+// Query lets the local victim decrypt and unpad an encrypted message.
+func (o *LocalOracle) Query(encryptedMessage []byte) (*oracle.Response, error) {
+	startTime := time.Now()
+
+	if len(encryptedMessage) < o.blockSize<<1 || len(encryptedMessage)%o.blockSize != 0 {
+		return &oracle.Response{
+			Status:  LocalStatusInvalidLength,
+			Body:    []byte(localBodyInvalidLength),
+			Latency: time.Since(startTime),
+		}, nil
+	}
+
+	_, err := DecryptAndUnpad(encryptedMessage, o.blockSize)
+	latency := time.Since(startTime)
+
+	if err != nil {
+		return &oracle.Response{
+			Status:  LocalStatusInvalidPadding,
+			Body:    []byte(localBodyInvalidPadding),
+			Latency: latency,
+		}, nil
+	}
+
+	return &oracle.Response{
+		Status:  LocalStatusOk,
+		Body:    []byte(localBodyOk),
+		Latency: latency,
+	}, nil
+}
+
+// NewLocalPaddingOracle creates a new [LocalPaddingOracle] for the block size of the victim.
+func NewLocalPaddingOracle(blockSize int) *LocalPaddingOracle {
+	return &LocalPaddingOracle{blockSize: blockSize}
+}
+
+// HasValidPadding lets the local victim decrypt and unpad an encrypted message
+// and checks whether this resulted in a padding error.
+func (o *LocalPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	_, err := DecryptAndUnpad(encryptedMessage, o.blockSize)
+	return err == nil, nil
+}
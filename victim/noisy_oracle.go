@@ -0,0 +1,89 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Use the random source that can be seeded.
+//    2026-10-18: V2.0.0: Moved to package victim.
+//
+
+// This file contains a padding oracle that simulates a noisy victim.
+//
+// A real victim does not always answer reliably.
+// E.g., a timing oracle sometimes classifies a response wrongly or a network connection fails.
+// The noisy oracle wraps another padding oracle and randomly falsifies its answers or fails.
+
+package victim
+
+import (
+	"padora/oracle"
+	"padora/randomsource"
+)
+
+// ******** Public types ********
+
+// NoisyPaddingOracle is a [oracle.PaddingOracle] that randomly falsifies the answers of a backend.
+type NoisyPaddingOracle struct {
+	backend           oracle.PaddingOracle
+	falsePositiveRate float64
+	falseNegativeRate float64
+	errorRate         float64
+}
+
+// ******** Public functions ********
+
+// NewNoisyPaddingOracle creates a new [NoisyPaddingOracle].
+// falsePositiveRate is the probability that an invalid padding is reported as valid.
+// falseNegativeRate is the probability that a valid padding is reported as invalid.
+// errorRate is the probability that the oracle fails with [oracle.ErrTransientFailure].
+func NewNoisyPaddingOracle(
+	backend oracle.PaddingOracle,
+	falsePositiveRate float64,
+	falseNegativeRate float64,
+	errorRate float64) *NoisyPaddingOracle {
+	return &NoisyPaddingOracle{
+		backend:           backend,
+		falsePositiveRate: falsePositiveRate,
+		falseNegativeRate: falseNegativeRate,
+		errorRate:         errorRate,
+	}
+}
+
+// HasValidPadding asks the backend and randomly falsifies its answer.
+func (o *NoisyPaddingOracle) HasValidPadding(encryptedMessage []byte) (bool, error) {
+	if randomsource.Float64() < o.errorRate {
+		return false, oracle.ErrTransientFailure
+	}
+
+	isValid, err := o.backend.HasValidPadding(encryptedMessage)
+	if err != nil {
+		return false, err
+	}
+
+	if isValid {
+		return randomsource.Float64() >= o.falseNegativeRate, nil
+	}
+
+	return randomsource.Float64() < o.falsePositiveRate, nil
+}
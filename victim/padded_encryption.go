@@ -0,0 +1,88 @@
+//
+// SPDX-FileCopyrightText: Copyright 2024 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 2.0.0
+//
+// Change history:
+//    2024-06-22: V1.0.0: Created.
+//    2026-10-18: V1.1.0: Unpad function is selectable.
+//    2026-10-18: V1.2.0: Padding scheme is selectable.
+//    2026-10-18: V2.0.0: Moved to package victim.
+//
+
+// This file contains the functions that process encryption and padding.
+
+package victim
+
+import (
+	"errors"
+	"padora/padding"
+)
+
+// ******** Public variables ********
+
+// ErrInvalidMessageLength signals that the length of an encrypted message is not a multiple of the block size,
+// or that the message does not contain an encrypted block after the initialization vector.
+var ErrInvalidMessageLength = errors.New(`invalid message length`)
+
+// ******** Private variables ********
+
+// modPaddingScheme is the padding scheme that is used by the victim.
+var modPaddingScheme padding.Scheme = padding.Pkcs7{}
+
+// modUnpad is the unpad function that is used by the victim.
+var modUnpad = padding.Unpad
+
+// ******** Public functions ********
+
+// UsePaddingScheme selects the padding scheme the victim uses.
+func UsePaddingScheme(scheme padding.Scheme) {
+	modPaddingScheme = scheme
+	modUnpad = scheme.Unpad
+}
+
+// UseConstantTimeUnpad selects whether the victim uses the constant-time unpad function,
+// or the one that returns early at the first invalid padding byte.
+// The constant-time unpad function is only available for PKCS#7.
+func UseConstantTimeUnpad(useConstantTime bool) {
+	if useConstantTime {
+		modUnpad = padding.ConstantTimeUnpad
+	} else {
+		modUnpad = modPaddingScheme.Unpad
+	}
+}
+
+// PadAndEncrypt pads and encrypts a clear message.
+func PadAndEncrypt(clearMessage []byte, blockSize int) []byte {
+	return Encrypt(modPaddingScheme.Pad(clearMessage, blockSize))
+}
+
+// DecryptAndUnpad decrypts and unpads a concatenation of an initialization vector and an encrypted message.
+func DecryptAndUnpad(compoundEncryptedMessage []byte, blockSize int) ([]byte, error) {
+	messageLength := len(compoundEncryptedMessage)
+	if messageLength < blockSize<<1 || messageLength%blockSize != 0 {
+		return nil, ErrInvalidMessageLength
+	}
+
+	decryptedMessage := Decrypt(compoundEncryptedMessage)
+	return modUnpad(decryptedMessage, blockSize)
+}
@@ -0,0 +1,103 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains a TCP server that lets the victim answer requests in the line protocol.
+//
+// Every connection may send many requests without waiting for the answers (pipelining).
+// The answers are written in the order of the requests and are flushed when no more requests are waiting.
+
+package victim
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// ******** Private variables ********
+
+// answerMutex makes sure that only one request is answered at a time,
+// as the victim uses a shared decryption buffer.
+var answerMutex sync.Mutex
+
+// ******** Public functions ********
+
+// ServeLineProtocol accepts connections on a listener and answers their requests in the line protocol
+// until the context is cancelled.
+func ServeLineProtocol(ctx context.Context, listener net.Listener, blockSize int) error {
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		go serveConnection(connection, blockSize)
+	}
+}
+
+// ******** Private functions ********
+
+// serveConnection answers the requests of a connection until it is closed.
+func serveConnection(connection net.Conn, blockSize int) {
+	defer func() { _ = connection.Close() }()
+
+	reader := bufio.NewReader(connection)
+	writer := bufio.NewWriter(connection)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			_ = writer.Flush()
+			return
+		}
+
+		answerMutex.Lock()
+		answer := AnswerLine(line, blockSize)
+		answerMutex.Unlock()
+
+		_, _ = writer.WriteString(answer)
+		_ = writer.WriteByte('\n')
+
+		// Answers are only sent, when all requests that have arrived have been answered.
+		if reader.Buffered() == 0 {
+			if err = writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+//
+// SPDX-FileCopyrightText: Copyright 2026 Frank Schwab
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileType: SOURCE
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// You may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Author: Frank Schwab
+//
+// Version: 1.0.0
+//
+// Change history:
+//    2026-10-18: V1.0.0: Created.
+//
+
+// This file contains a toy block cipher with a block size of 4 or 8 bytes.
+//
+// It is a Feistel network: the block is split into a left and a right half,
+// and in every round the left half is XORed with a round function of the right half, then the halves are swapped.
+// Decryption runs the same rounds in reverse order, so the round function does not need to be invertible.
+//
+// The toy cipher is *not* secure. It only shows that the padding oracle attack does not depend on the cipher,
+// and its small blocks make the tables of the attack short.
+
+package victim
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+)
+
+// ******** Public types ********
+
+// ToyCipher is a toy block cipher that implements [cipher.Block].
+type ToyCipher struct {
+	blockSize int
+	roundKeys [][]byte
+}
+
+// ******** Private constants ********
+
+// toyRounds is the number of rounds of the toy cipher.
+const toyRounds = 8
+
+// toyRotation is the number of bits a byte is rotated to the left in the round function.
+const toyRotation = 3
+
+// toyRoundConstant is XORed with the round number into every round key, so that the round keys differ.
+const toyRoundConstant = 0x9e
+
+// ******** Public variables ********
+
+// ToyBlockSizes are the block sizes the toy cipher supports.
+var ToyBlockSizes = []int{4, 8}
+
+// ErrInvalidToyBlockSize signals that the toy cipher does not support a block size.
+var ErrInvalidToyBlockSize = errors.New(`toy cipher only supports block sizes of 4 and 8 bytes`)
+
+// ErrEmptyToyKey signals that the key of the toy cipher is empty.
+var ErrEmptyToyKey = errors.New(`toy cipher key must not be empty`)
+
+// ******** Public functions ********
+
+// NewToyCipher creates a new toy cipher with a key and a block size.
+// The key may have any length but 0.
+func NewToyCipher(key []byte, blockSize int) (*ToyCipher, error) {
+	if !slices.Contains(ToyBlockSizes, blockSize) {
+		return nil, ErrInvalidToyBlockSize
+	}
+
+	if len(key) == 0 {
+		return nil, ErrEmptyToyKey
+	}
+
+	// Every round key has the length of a half block and is taken from the key bytes one after the other.
+	halfSize := blockSize >> 1
+	roundKeys := make([][]byte, toyRounds)
+	for round := range roundKeys {
+		roundKeys[round] = make([]byte, halfSize)
+		for i := range roundKeys[round] {
+			roundKeys[round][i] = key[(round*halfSize+i)%len(key)] ^ byte(round*toyRoundConstant)
+		}
+	}
+
+	return &ToyCipher{blockSize: blockSize, roundKeys: roundKeys}, nil
+}
+
+// BlockSize returns the block size of the toy cipher.
+func (c *ToyCipher) BlockSize() int {
+	return c.blockSize
+}
+
+// Encrypt encrypts the first block of src into dst.
+// dst and src may overlap entirely.
+func (c *ToyCipher) Encrypt(dst []byte, src []byte) {
+	left, right := c.splitBlock(src)
+	for round := 0; round < toyRounds; round++ {
+		// (L, R) -> (R, L XOR F(R))
+		xorInto(left, toyRound(right, c.roundKeys[round]))
+		left, right = right, left
+	}
+
+	c.joinBlock(dst, left, right)
+}
+
+// Decrypt decrypts the first block of src into dst.
+// dst and src may overlap entirely.
+func (c *ToyCipher) Decrypt(dst []byte, src []byte) {
+	left, right := c.splitBlock(src)
+	for round := toyRounds - 1; round >= 0; round-- {
+		// (L, R) -> (R XOR F(L), L)
+		xorInto(right, toyRound(left, c.roundKeys[round]))
+		left, right = right, left
+	}
+
+	c.joinBlock(dst, left, right)
+}
+
+// ******** Private functions ********
+
+// splitBlock copies the first block of src into a left and a right half.
+func (c *ToyCipher) splitBlock(src []byte) ([]byte, []byte) {
+	if len(src) < c.blockSize {
+		panic(`toy cipher: input not full block`)
+	}
+
+	halfSize := c.blockSize >> 1
+	return slices.Clone(src[:halfSize]), slices.Clone(src[halfSize:c.blockSize])
+}
+
+// joinBlock copies a left and a right half into the first block of dst.
+func (c *ToyCipher) joinBlock(dst []byte, left []byte, right []byte) {
+	if len(dst) < c.blockSize {
+		panic(`toy cipher: output not full block`)
+	}
+
+	copy(dst, left)
+	copy(dst[len(left):], right)
+}
+
+// toyRound is the round function of the toy cipher.
+// Every byte of the half block is XORed with the round key, rotated and added to the next byte.
+// The mix of XOR and addition makes the round function non-linear.
+func toyRound(half []byte, roundKey []byte) []byte {
+	result := make([]byte, len(half))
+	for i, b := range half {
+		result[i] = bits.RotateLeft8(b^roundKey[i], toyRotation) + half[(i+1)%len(half)]
+	}
+
+	return result
+}
+
+// xorInto XORs source into destination.
+func xorInto(destination []byte, source []byte) {
+	for i, b := range source {
+		destination[i] ^= b
+	}
+}